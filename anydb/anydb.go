@@ -8,17 +8,17 @@ package anydb
 import (
 	"fmt"
 	"log"
-	"path/filepath"
-	"runtime"
-	"strings"
 
 	"github.com/aerth/mostly/ncode"
 	"go.etcd.io/bbolt"
 )
 
-type byteslike interface {
-	~string | ~[]byte
-}
+type byteslike = ncode.Byteslike
+
+// Debug logs reads (nil is off), separate from ncode.Debug
+//
+// eg: anydb.Debug = ncode.NewDebugger(os.Stderr)
+var Debug *ncode.Debugger
 
 // FetchDB anything magic
 func FetchDB[T any, K byteslike](db *bbolt.DB, bucket string, key ...K) (T, error) {
@@ -38,51 +38,30 @@ func FetchDB_Tx[T any, K byteslike](tx *bbolt.Tx, bucket string, key ...K) (T, e
 		var v T
 		return v, bbolt.ErrBucketNotFound
 	}
-	if ncode.DebugJsonRequests {
-		var caller string
-		for i := 1; i <= 6; i++ {
-			_, file, num, ok := runtime.Caller(i)
-			if !ok {
-				break
-			}
-			fname := filepath.Base(file)
-			if strings.HasPrefix(fname, "asm_") || strings.HasPrefix(fname, "xxxx_some_other_name_prefix") {
-				break
-			}
-			caller += fmt.Sprintf("%s:%d ", fname, num)
-		}
-		// log.Println(caller, "serving error:", err)
-		log.Println(caller, "fetchdb: read", bucket, string(key[0]))
-	}
 	l := len(key)
 	if l == 0 {
 		var v T
 		return v, fmt.Errorf("no key?")
 	}
+	Debug.Printf("fetchdb: read %s %s", bucket, string(key[0]))
 	if l == 1 {
 		if len(key[0]) == 0 {
 			var v T
 			return v, fmt.Errorf("empty key?")
 		}
-		return ncode.DecodeJson[T](bu.Get([]byte(key[0])))
-	}
-	if ncode.DebugJsonRequests {
-		log.Println("checking", l, "nested", string(key[0]), string(key[1]))
+		return ncode.Decode[T](bu.Get([]byte(key[0])))
 	}
+	Debug.Printf("checking %d nested %s %s", l, string(key[0]), string(key[1]))
 	for i := 0; i < l-1; i++ {
-		if ncode.DebugJsonRequests {
-			log.Printf("checking: %q (hex: %02x)", string(key[i]), key[i])
-		}
+		Debug.Printf("checking: %q (hex: %02x)", string(key[i]), key[i])
 		bu = bu.Bucket([]byte(key[i]))
 		if bu == nil {
 			var v T
-			if ncode.DebugJsonRequests {
-				log.Printf("fail %d: bucket %s is nil", i, string(key[i]))
-			}
+			Debug.Printf("fail %d: bucket %s is nil", i, string(key[i]))
 			return v, bbolt.ErrBucketNotFound
 		}
 	}
-	return ncode.DecodeJson[T](bu.Get([]byte(key[l-1])))
+	return ncode.Decode[T](bu.Get([]byte(key[l-1])))
 
 }
 
@@ -130,7 +109,11 @@ func StoreDB_Tx[K byteslike](tx *bbolt.Tx, bucket string, key K, val any) error
 	if bu == nil {
 		return bbolt.ErrBucketNotFound
 	}
-	return bu.Put([]byte(key), ncode.Json(val))
+	buf, err := ncode.Encode(val)
+	if err != nil {
+		return err
+	}
+	return bu.Put([]byte(key), buf)
 }
 func StoreDBNested[K byteslike](db *bbolt.DB, bucket string, key []K, val any) error {
 	return db.Update(func(tx *bbolt.Tx) error {
@@ -154,5 +137,9 @@ func StoreDBNested_Tx[K byteslike](tx *bbolt.Tx, bucket string, key []K, val any
 			return fmt.Errorf("bad nested lookup")
 		}
 	}
-	return bu.Put([]byte(key[l-1]), ncode.Json(val))
+	buf, err := ncode.Encode(val)
+	if err != nil {
+		return err
+	}
+	return bu.Put([]byte(key[l-1]), buf)
 }
@@ -0,0 +1,64 @@
+package anydb
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"go.etcd.io/bbolt"
+)
+
+type item struct {
+	Name string
+}
+
+func testDB(t *testing.T) *bbolt.DB {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "test.db"), 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	err = db.Update(func(tx *bbolt.Tx) error {
+		bu, err := tx.CreateBucket([]byte("items"))
+		if err != nil {
+			return err
+		}
+		_, err = bu.CreateBucket([]byte("b-nested"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"a", "b", "c", "d"} {
+		if err := StoreDB(db, "items", k, item{Name: k}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestForEachRange(t *testing.T) {
+	db := testDB(t)
+	var names string
+	err := ForEach(db, "items", func(k []byte, v item) error {
+		names += v.Name
+		return nil
+	})
+	if err != nil || names != "abcd" {
+		t.Fatalf("ForEach: %q %v", names, err)
+	}
+	errStop := errors.New("stop")
+	if err := ForEach(db, "items", func([]byte, item) error { return errStop }); err != errStop {
+		t.Fatalf("ForEach error not returned: %v", err)
+	}
+	if err := ForEach(db, "missing", func([]byte, item) error { return nil }); err != bbolt.ErrBucketNotFound {
+		t.Fatalf("missing bucket: %v", err)
+	}
+	kvs, err := Range[item](db, "items", []byte("b"), []byte("d"))
+	if err != nil || len(kvs) != 2 || string(kvs[0].Key) != "b" || kvs[1].Value.Name != "c" {
+		t.Fatalf("Range: %v %v", kvs, err)
+	}
+	if kvs, err := Range[item](db, "items", nil, nil); err != nil || len(kvs) != 4 {
+		t.Fatalf("Range all: %v %v", kvs, err)
+	}
+}
@@ -0,0 +1,90 @@
+package anydb
+
+import (
+	"bytes"
+
+	"github.com/aerth/mostly/ncode"
+	"go.etcd.io/bbolt"
+)
+
+// KV is one decoded key/value pair, see Range
+type KV[T any] struct {
+	Key   []byte
+	Value T
+}
+
+// ForEach decodes every value in bucket (key order), stopping at the first error from decoding or fn.
+//
+// key is only valid during fn (copy it to keep it). Nested buckets are skipped.
+func ForEach[T any](db *bbolt.DB, bucket string, fn func(key []byte, v T) error) error {
+	return db.View(func(tx *bbolt.Tx) error {
+		return ForEach_Tx(tx, bucket, fn)
+	})
+}
+
+// ForEach_Tx see ForEach (but in a Tx)
+func ForEach_Tx[T any](tx *bbolt.Tx, bucket string, fn func(key []byte, v T) error) error {
+	bu := tx.Bucket([]byte(bucket))
+	if bu == nil {
+		return bbolt.ErrBucketNotFound
+	}
+	return bu.ForEach(func(k, buf []byte) error {
+		if buf == nil { // nested bucket
+			return nil
+		}
+		v, err := decodeKV[T]("foreach", bucket, k, buf)
+		if err != nil {
+			return err
+		}
+		return fn(k, v)
+	})
+}
+
+// Range decodes values with start <= key < end (key order). nil start is the first key, nil end is through the last.
+//
+//	// all keys with prefix "user:"
+//	users, err := anydb.Range[User](db, "users", []byte("user:"), []byte("user;"))
+func Range[T any](db *bbolt.DB, bucket string, start, end []byte) ([]KV[T], error) {
+	var out []KV[T]
+	err := db.View(func(tx *bbolt.Tx) error {
+		var err error
+		out, err = Range_Tx[T](tx, bucket, start, end)
+		return err
+	})
+	return out, err
+}
+
+// Range_Tx see Range (but in a Tx). Keys are copied, safe to use after the Tx.
+func Range_Tx[T any](tx *bbolt.Tx, bucket string, start, end []byte) ([]KV[T], error) {
+	bu := tx.Bucket([]byte(bucket))
+	if bu == nil {
+		return nil, bbolt.ErrBucketNotFound
+	}
+	var (
+		out  []KV[T]
+		c    = bu.Cursor()
+		k, b []byte
+	)
+	if start == nil {
+		k, b = c.First()
+	} else {
+		k, b = c.Seek(start)
+	}
+	for ; k != nil && (end == nil || bytes.Compare(k, end) < 0); k, b = c.Next() {
+		if b == nil { // nested bucket
+			continue
+		}
+		v, err := decodeKV[T]("range", bucket, k, b)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, KV[T]{Key: bytes.Clone(k), Value: v})
+	}
+	return out, nil
+}
+
+// decodeKV for caller (for the debug log)
+func decodeKV[T any](caller, bucket string, k, buf []byte) (T, error) {
+	Debug.Printf("%s: read %s %s", caller, bucket, string(k))
+	return ncode.Decode[T](buf)
+}
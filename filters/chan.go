@@ -0,0 +1,57 @@
+package filters
+
+import "context"
+
+// FilterChan sends only the items received from in that pass the filter.
+//
+// The returned channel is closed when in is closed or ctx is done (a cancellable.Cancellable or superchan context works too).
+// Items received but not yet sent when ctx is done are dropped.
+func FilterChan[T any](ctx context.Context, in <-chan T, keepfn func(a T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				if !keepfn(v) {
+					continue
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// MapChan sends fn applied to each item received from in, see FilterChan
+func MapChan[T any, K any](ctx context.Context, in <-chan T, fn func(a T) K) <-chan K {
+	out := make(chan K)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- fn(v):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
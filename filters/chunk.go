@@ -0,0 +1,20 @@
+package filters
+
+// Chunk splits a into batches of size (the last may be smaller), eg: for batch writes or pagination.
+//
+// Chunks share the backing array of a, but are capacity-limited so appending to one does not overwrite the next.
+// Panics if size < 1.
+func Chunk[S ~[]T, T any](a S, size int) []S {
+	if size < 1 {
+		panic("filters: Chunk size must be at least 1")
+	}
+	if len(a) == 0 {
+		return nil
+	}
+	out := make([]S, 0, (len(a)+size-1)/size)
+	for i := 0; i < len(a); i += size {
+		end := min(i+size, len(a))
+		out = append(out, a[i:end:end])
+	}
+	return out
+}
@@ -1,5 +1,7 @@
 package filters
 
+import "context"
+
 // FilterInPlace modifies the slice in place. See Warning.
 //
 // Warning: All slices that share the same backing array will be modified and need to be replaced by the return value.
@@ -36,3 +38,37 @@ func FilterCopy[S ~[]T, T any](a S, keepfn func(a T) bool) (out S) {
 	}
 	return
 }
+
+// Partition returns new slices of the items that pass the filter (keep) and the rest (reject), in one pass.
+// The original slice is not modified.
+func Partition[S ~[]T, T any](a S, keepfn func(a T) bool) (keep, reject S) {
+	l := len(a)
+	for i := 0; i < l; i++ {
+		if keepfn(a[i]) {
+			keep = append(keep, a[i])
+		} else {
+			reject = append(reject, a[i])
+		}
+	}
+	return
+}
+
+// FilterErr is FilterCopy with a context and an error-returning filter, eg: for lookups in a DB or API.
+//
+// Stops at the first error or when ctx is done, returning the items that passed so far and the error (or context cause).
+func FilterErr[S ~[]T, T any](ctx context.Context, a S, keepfn func(ctx context.Context, a T) (bool, error)) (out S, err error) {
+	l := len(a)
+	for i := 0; i < l; i++ {
+		if ctx.Err() != nil {
+			return out, context.Cause(ctx)
+		}
+		keep, err := keepfn(ctx, a[i])
+		if err != nil {
+			return out, err
+		}
+		if keep {
+			out = append(out, a[i])
+		}
+	}
+	return out, nil
+}
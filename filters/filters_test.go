@@ -0,0 +1,257 @@
+package filters
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMapReduceGroupBy(t *testing.T) {
+	a := []int{1, 2, 3, 4, 5}
+	if got := Map(a, strconv.Itoa); !slices.Equal(got, []string{"1", "2", "3", "4", "5"}) {
+		t.Fatalf("Map: %v", got)
+	}
+	if got := Map([]int(nil), strconv.Itoa); got != nil {
+		t.Fatalf("Map nil: %v", got)
+	}
+	if got := Reduce(a, 10, func(sum, v int) int { return sum + v }); got != 25 {
+		t.Fatalf("Reduce: %d", got)
+	}
+	groups := GroupBy(a, func(v int) bool { return v%2 == 0 })
+	if !slices.Equal(groups[true], []int{2, 4}) || !slices.Equal(groups[false], []int{1, 3, 5}) {
+		t.Fatalf("GroupBy: %v", groups)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	a := []int{1, 2, 3, 4, 5}
+	chunks := Chunk(a, 2)
+	if len(chunks) != 3 || !slices.Equal(chunks[2], []int{5}) || !slices.Equal(chunks[0], []int{1, 2}) {
+		t.Fatalf("Chunk: %v", chunks)
+	}
+	_ = append(chunks[0], 99)
+	if a[2] != 3 {
+		t.Fatal("append to chunk overwrote next chunk")
+	}
+	if Chunk([]int{}, 3) != nil {
+		t.Fatal("expected nil for empty")
+	}
+}
+
+func TestUnique(t *testing.T) {
+	a := []string{"b", "a", "b", "c", "a"}
+	if got := Unique(a); !slices.Equal(got, []string{"b", "a", "c"}) {
+		t.Fatalf("Unique: %v", got)
+	}
+	if a[2] != "b" {
+		t.Fatal("input modified")
+	}
+	type user struct{ id, src string }
+	users := []user{{"1", "db"}, {"2", "db"}, {"1", "api"}}
+	if got := DedupeBy(users, func(u user) string { return u.id }); len(got) != 2 || got[0].src != "db" {
+		t.Fatalf("DedupeBy: %v", got)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	even, odd := Partition([]int{1, 2, 3, 4, 5}, func(v int) bool { return v%2 == 0 })
+	if !slices.Equal(even, []int{2, 4}) || !slices.Equal(odd, []int{1, 3, 5}) {
+		t.Fatalf("Partition: %v %v", even, odd)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	a := make([]int, 100)
+	for i := range a {
+		a[i] = i
+	}
+	ctx := context.Background()
+	got, err := FilterParallel(ctx, a, func(v int) bool { return v%10 == 0 }, 8)
+	if err != nil || !slices.Equal(got, []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90}) {
+		t.Fatalf("FilterParallel: %v %v", got, err)
+	}
+	strs, err := MapParallel(ctx, a, strconv.Itoa, 0)
+	if err != nil || strs[42] != "42" || len(strs) != 100 {
+		t.Fatalf("MapParallel: %v", err)
+	}
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := FilterParallel(cctx, a, func(int) bool { return true }, 4); err == nil {
+		t.Fatal("expected cancel error")
+	}
+}
+
+func TestFilterErr(t *testing.T) {
+	errBad := errors.New("bad")
+	got, err := FilterErr(context.Background(), []int{1, 2, 3, 4}, func(_ context.Context, v int) (bool, error) {
+		if v == 3 {
+			return false, errBad
+		}
+		return true, nil
+	})
+	if err != errBad || !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("FilterErr: %v %v", got, err)
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	got, err = FilterErr(ctx, []int{1, 2, 3}, func(_ context.Context, v int) (bool, error) {
+		if v == 2 {
+			cancel(errBad)
+		}
+		return true, nil
+	})
+	if err != errBad || !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("FilterErr cancelled: %v %v", got, err)
+	}
+}
+
+func TestSets(t *testing.T) {
+	desired := []string{"a", "b", "c", "c"}
+	actual := []string{"b", "d"}
+	if got := Union(desired, actual); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("Union: %v", got)
+	}
+	if got := Intersect(desired, actual); !slices.Equal(got, []string{"b"}) {
+		t.Fatalf("Intersect: %v", got)
+	}
+	if got := Difference(desired, actual); !slices.Equal(got, []string{"a", "c"}) {
+		t.Fatalf("Difference: %v", got)
+	}
+	if got := Difference(actual, desired); !slices.Equal(got, []string{"d"}) {
+		t.Fatalf("Difference: %v", got)
+	}
+	type item struct{ id, v string }
+	byID := func(i item) string { return i.id }
+	got := IntersectBy([]item{{"1", "new"}, {"2", "new"}}, []item{{"1", "old"}}, byID)
+	if len(got) != 1 || got[0].v != "new" {
+		t.Fatalf("IntersectBy: %v", got)
+	}
+	if got := DifferenceInPlace([]string{"a", "b", "a"}, []string{"b"}); !slices.Equal(got, []string{"a", "a"}) {
+		t.Fatalf("DifferenceInPlace: %v", got)
+	}
+}
+
+func TestSortByTopN(t *testing.T) {
+	less := func(x, y int) bool { return x > y } // descending
+	a := []int{5, 1, 9, 3, 7, 2, 8}
+	want := SortBy(slices.Clone(a), less)
+	if !slices.Equal(want, []int{9, 8, 7, 5, 3, 2, 1}) {
+		t.Fatalf("SortBy: %v", want)
+	}
+	for n := 0; n <= len(a)+1; n++ {
+		got := TopN(a, n, less)
+		if !slices.Equal(got, want[:min(n, len(a))]) {
+			t.Fatalf("TopN %d: %v", n, got)
+		}
+	}
+	if a[0] != 5 {
+		t.Fatal("TopN modified input")
+	}
+}
+
+func TestFind(t *testing.T) {
+	a := []int{1, 4, 6, 7}
+	even := func(v int) bool { return v%2 == 0 }
+	if v, ok := Find(a, even); !ok || v != 4 {
+		t.Fatalf("Find: %d %v", v, ok)
+	}
+	if _, ok := Find(a, func(v int) bool { return v > 10 }); ok {
+		t.Fatal("Find: expected not found")
+	}
+	if i := FindIndex(a, even); i != 1 {
+		t.Fatalf("FindIndex: %d", i)
+	}
+	if !ContainsFunc(a, even) || ContainsFunc([]int(nil), even) {
+		t.Fatal("ContainsFunc")
+	}
+	if n := Count(a, even); n != 2 {
+		t.Fatalf("Count: %d", n)
+	}
+}
+
+func TestSeq(t *testing.T) {
+	pulled := 0
+	naturals := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	seq := TakeSeq(MapSeq(FilterSeq(naturals, func(v int) bool { return v%3 == 0 }), strconv.Itoa), 4)
+	if got := slices.Collect(seq); !slices.Equal(got, []string{"0", "3", "6", "9"}) {
+		t.Fatalf("seq: %v", got)
+	}
+	if pulled != 10 {
+		t.Fatalf("not lazy, pulled %d", pulled)
+	}
+	if got := slices.Collect(TakeSeq(slices.Values([]int{1}), 0)); len(got) != 0 {
+		t.Fatalf("TakeSeq 0: %v", got)
+	}
+}
+
+func TestChan(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		for i := range 6 {
+			in <- i
+		}
+		close(in)
+	}()
+	ctx := context.Background()
+	var got []string
+	for v := range MapChan(ctx, FilterChan(ctx, in, func(v int) bool { return v%2 == 1 }), strconv.Itoa) {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []string{"1", "3", "5"}) {
+		t.Fatalf("chan: %v", got)
+	}
+	cctx, cancel := context.WithCancel(ctx)
+	out := FilterChan(cctx, make(chan int), func(int) bool { return true })
+	cancel()
+	if _, ok := <-out; ok {
+		t.Fatal("expected closed after cancel")
+	}
+}
+
+func TestReshape(t *testing.T) {
+	a := []int{1, 2, 3}
+	if got := ReverseCopy(a); !slices.Equal(got, []int{3, 2, 1}) || a[0] != 1 {
+		t.Fatalf("ReverseCopy: %v %v", got, a)
+	}
+	if got := ReverseInPlace(a); !slices.Equal(got, []int{3, 2, 1}) || a[0] != 3 {
+		t.Fatalf("ReverseInPlace: %v", got)
+	}
+	if got := Flatten([][]int{{1}, nil, {2, 3}}); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("Flatten: %v", got)
+	}
+	z := Zip([]string{"a", "b", "c"}, []int{1, 2})
+	if len(z) != 2 || z[1].First != "b" || z[1].Second != 2 {
+		t.Fatalf("Zip: %v", z)
+	}
+}
+
+func TestShuffleSample(t *testing.T) {
+	a := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	s1 := Shuffle(slices.Clone(a), rand.New(rand.NewPCG(1, 2)))
+	s2 := Shuffle(slices.Clone(a), rand.New(rand.NewPCG(1, 2)))
+	if !slices.Equal(s1, s2) {
+		t.Fatalf("Shuffle not deterministic: %v %v", s1, s2)
+	}
+	if slices.Sort(s1); !slices.Equal(s1, a) {
+		t.Fatalf("Shuffle lost items: %v", s1)
+	}
+	got := Sample(a, 3, rand.New(rand.NewPCG(3, 4)))
+	if len(got) != 3 || len(Unique(got)) != 3 {
+		t.Fatalf("Sample: %v", got)
+	}
+	if !slices.Equal(got, Sample(a, 3, rand.New(rand.NewPCG(3, 4)))) {
+		t.Fatal("Sample not deterministic")
+	}
+	if a[0] != 1 || len(Sample(a, 100, nil)) != len(a) || Sample(a, 0, nil) != nil {
+		t.Fatal("Sample bounds")
+	}
+}
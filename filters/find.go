@@ -0,0 +1,36 @@
+package filters
+
+// Find returns the first item that passes fn, without allocating
+func Find[S ~[]T, T any](a S, fn func(a T) bool) (T, bool) {
+	if i := FindIndex(a, fn); i >= 0 {
+		return a[i], true
+	}
+	var zero T
+	return zero, false
+}
+
+// FindIndex returns the index of the first item that passes fn, or -1
+func FindIndex[S ~[]T, T any](a S, fn func(a T) bool) int {
+	for i := range a {
+		if fn(a[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ContainsFunc reports whether any item passes fn (instead of len(FilterCopy(a, fn)) > 0)
+func ContainsFunc[S ~[]T, T any](a S, fn func(a T) bool) bool {
+	return FindIndex(a, fn) >= 0
+}
+
+// Count returns how many items pass fn
+func Count[S ~[]T, T any](a S, fn func(a T) bool) int {
+	n := 0
+	for i := range a {
+		if fn(a[i]) {
+			n++
+		}
+	}
+	return n
+}
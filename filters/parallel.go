@@ -0,0 +1,30 @@
+package filters
+
+import (
+	"context"
+
+	"github.com/aerth/mostly/ncode"
+)
+
+// FilterParallel is FilterCopy using up to workers goroutines, for slow (I/O) filters.
+//
+// Output order matches input order. If workers < 1, uses GOMAXPROCS.
+// If ctx is cancelled, returns the items that passed so far and the context cause.
+func FilterParallel[S ~[]T, T any](ctx context.Context, a S, keepfn func(a T) bool, workers int) (S, error) {
+	keep, err := ncode.TwistAnyParallel(ctx, a, keepfn, workers)
+	var out S
+	for i := range a {
+		if keep[i] {
+			out = append(out, a[i])
+		}
+	}
+	return out, err
+}
+
+// MapParallel is Map using up to workers goroutines, for slow (I/O) transforms, see ncode.TwistAnyParallel
+//
+// Output order matches input order. If workers < 1, uses GOMAXPROCS.
+// If ctx is cancelled, unfinished items are zero values and the context cause is returned.
+func MapParallel[S ~[]T, T any, K any](ctx context.Context, a S, fn func(a T) K, workers int) ([]K, error) {
+	return ncode.TwistAnyParallel(ctx, a, fn, workers)
+}
@@ -0,0 +1,37 @@
+package filters
+
+import "math/rand/v2"
+
+// Shuffle a in place and returns it, using rng (nil is the global source). See FilterInPlace Warning.
+//
+// For deterministic tests: filters.Shuffle(a, rand.New(rand.NewPCG(1, 2)))
+func Shuffle[S ~[]T, T any](a S, rng *rand.Rand) S {
+	swap := func(i, j int) { a[i], a[j] = a[j], a[i] }
+	if rng == nil {
+		rand.Shuffle(len(a), swap)
+	} else {
+		rng.Shuffle(len(a), swap)
+	}
+	return a
+}
+
+// Sample returns a new slice of n random items of a (each index picked at most once), using rng (nil is the global source).
+// If n >= len(a), all items are returned shuffled. The original slice is not modified.
+func Sample[S ~[]T, T any](a S, n int, rng *rand.Rand) S {
+	if n <= 0 || len(a) == 0 {
+		return nil
+	}
+	out := make(S, len(a))
+	copy(out, a)
+	n = min(n, len(out))
+	intn := rand.IntN
+	if rng != nil {
+		intn = rng.IntN
+	}
+	// partial Fisher-Yates, only the first n
+	for i := 0; i < n; i++ {
+		j := i + intn(len(out)-i)
+		out[i], out[j] = out[j], out[i]
+	}
+	return out[:n:n]
+}
@@ -0,0 +1,51 @@
+package filters
+
+// ReverseInPlace reverses a in place and returns it. See FilterInPlace Warning.
+func ReverseInPlace[S ~[]T, T any](a S) S {
+	for i, j := 0, len(a)-1; i < j; i, j = i+1, j-1 {
+		a[i], a[j] = a[j], a[i]
+	}
+	return a
+}
+
+// ReverseCopy returns a new slice in reverse order. The original slice is not modified.
+func ReverseCopy[S ~[]T, T any](a S) S {
+	if a == nil {
+		return nil
+	}
+	out := make(S, len(a))
+	for i := range a {
+		out[len(a)-1-i] = a[i]
+	}
+	return out
+}
+
+// Flatten returns a new slice of all items of each slice, in order
+func Flatten[S ~[]T, T any](a []S) S {
+	n := 0
+	for i := range a {
+		n += len(a[i])
+	}
+	out := make(S, 0, n)
+	for i := range a {
+		out = append(out, a[i]...)
+	}
+	return out
+}
+
+// Pair is one item of Zip
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs items of a and b by index, the result is as long as the shorter one
+//
+//	for _, kv := range filters.Zip(keys, values) { fmt.Println(kv.First, kv.Second) }
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	out := make([]Pair[A, B], min(len(a), len(b)))
+	for i := range out {
+		out[i] = Pair[A, B]{a[i], b[i]}
+	}
+	return out
+}
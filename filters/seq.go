@@ -0,0 +1,45 @@
+package filters
+
+import "iter"
+
+// FilterSeq lazily yields only the items of seq that pass the filter
+//
+//	for v := range filters.FilterSeq(slices.Values(big), keep) { ... }
+func FilterSeq[T any](seq iter.Seq[T], keepfn func(a T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if keepfn(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// MapSeq lazily yields fn applied to each item of seq
+func MapSeq[T any, K any](seq iter.Seq[T], fn func(a T) K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for v := range seq {
+			if !yield(fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+// TakeSeq yields at most n items of seq, then stops seq
+func TakeSeq[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			if i++; i >= n {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package filters
+
+// Union returns a new slice of the items in a or b, without duplicates (order: a, then b)
+func Union[S ~[]T, T comparable](a, b S) S {
+	return UnionBy(a, b, identity[T])
+}
+
+// Intersect returns a new slice of the items of a that are also in b, without duplicates (order of a)
+func Intersect[S ~[]T, T comparable](a, b S) S {
+	return IntersectBy(a, b, identity[T])
+}
+
+// Difference returns a new slice of the items of a that are not in b, without duplicates (order of a)
+//
+//	toCreate := filters.Difference(desired, actual)
+//	toDelete := filters.Difference(actual, desired)
+func Difference[S ~[]T, T comparable](a, b S) S {
+	return DifferenceBy(a, b, identity[T])
+}
+
+// UnionBy is Union comparing keyfn keys, keeping the first item for each key
+func UnionBy[S ~[]T, T any, K comparable](a, b S, keyfn func(a T) K) S {
+	all := make(S, 0, len(a)+len(b))
+	return DedupeBy(append(append(all, a...), b...), keyfn)
+}
+
+// IntersectBy is Intersect comparing keyfn keys (items are from a)
+func IntersectBy[S ~[]T, T any, K comparable](a, b S, keyfn func(a T) K) S {
+	inb := keyset(b, keyfn)
+	return DedupeBy(FilterCopy(a, func(a T) bool {
+		_, ok := inb[keyfn(a)]
+		return ok
+	}), keyfn)
+}
+
+// DifferenceBy is Difference comparing keyfn keys
+func DifferenceBy[S ~[]T, T any, K comparable](a, b S, keyfn func(a T) K) S {
+	inb := keyset(b, keyfn)
+	return DedupeBy(FilterCopy(a, func(a T) bool {
+		_, ok := inb[keyfn(a)]
+		return !ok
+	}), keyfn)
+}
+
+// DifferenceInPlace is Difference but modifies a in place (duplicates in a are kept). See FilterInPlace Warning.
+func DifferenceInPlace[S ~[]T, T comparable](a, b S) S {
+	inb := keyset(b, identity[T])
+	return FilterInPlace(a, func(a T) bool {
+		_, ok := inb[a]
+		return !ok
+	})
+}
+
+func keyset[S ~[]T, T any, K comparable](a S, keyfn func(a T) K) map[K]struct{} {
+	m := make(map[K]struct{}, len(a))
+	for i := range a {
+		m[keyfn(a[i])] = struct{}{}
+	}
+	return m
+}
+
+func identity[T any](a T) T { return a }
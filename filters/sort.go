@@ -0,0 +1,70 @@
+package filters
+
+import "slices"
+
+// SortBy sorts a in place (stable) and returns it, less reports whether x goes before y
+//
+//	filters.SortBy(posts, func(x, y Post) bool { return x.Created.After(y.Created) }) // newest first
+func SortBy[S ~[]T, T any](a S, less func(x, y T) bool) S {
+	slices.SortStableFunc(a, func(x, y T) int {
+		switch {
+		case less(x, y):
+			return -1
+		case less(y, x):
+			return 1
+		}
+		return 0
+	})
+	return a
+}
+
+// TopN returns a new slice of the first n items as if a was sorted by less, without sorting all of a.
+//
+//	newest := filters.TopN(posts, 10, func(x, y Post) bool { return x.Created.After(y.Created) })
+func TopN[S ~[]T, T any](a S, n int, less func(x, y T) bool) S {
+	if n <= 0 || len(a) == 0 {
+		return nil
+	}
+	if n >= len(a) {
+		return SortBy(slices.Clone(a), less)
+	}
+	// max-heap of the best n so far, root is the worst of them
+	h := make(S, 0, n)
+	for i := range a {
+		if len(h) < n {
+			h = append(h, a[i])
+			siftUp(h, len(h)-1, less)
+		} else if less(a[i], h[0]) {
+			h[0] = a[i]
+			siftDown(h, 0, less)
+		}
+	}
+	return SortBy(h, less)
+}
+
+func siftUp[S ~[]T, T any](h S, i int, less func(x, y T) bool) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !less(h[parent], h[i]) {
+			return
+		}
+		h[parent], h[i] = h[i], h[parent]
+		i = parent
+	}
+}
+
+func siftDown[S ~[]T, T any](h S, i int, less func(x, y T) bool) {
+	for {
+		worst := i
+		for _, c := range [2]int{2*i + 1, 2*i + 2} {
+			if c < len(h) && less(h[worst], h[c]) {
+				worst = c
+			}
+		}
+		if worst == i {
+			return
+		}
+		h[i], h[worst] = h[worst], h[i]
+		i = worst
+	}
+}
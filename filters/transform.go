@@ -0,0 +1,34 @@
+package filters
+
+// Map returns a new slice with fn applied to each item (like ncode.TwistAny)
+func Map[S ~[]T, T any, K any](a S, fn func(a T) K) []K {
+	if a == nil {
+		return nil
+	}
+	out := make([]K, len(a))
+	for i := range a {
+		out[i] = fn(a[i])
+	}
+	return out
+}
+
+// Reduce folds the slice into one value, starting with initial
+//
+//	total := filters.Reduce(orders, 0, func(sum int, o Order) int { return sum + o.Amount })
+func Reduce[S ~[]T, T any, K any](a S, initial K, fn func(acc K, a T) K) K {
+	acc := initial
+	for i := range a {
+		acc = fn(acc, a[i])
+	}
+	return acc
+}
+
+// GroupBy returns items grouped by keyfn, each group in original order
+func GroupBy[S ~[]T, T any, K comparable](a S, keyfn func(a T) K) map[K]S {
+	out := make(map[K]S)
+	for i := range a {
+		k := keyfn(a[i])
+		out[k] = append(out[k], a[i])
+	}
+	return out
+}
@@ -0,0 +1,21 @@
+package filters
+
+// Unique returns a new slice without duplicates, keeping the first occurrence (order preserved)
+func Unique[S ~[]T, T comparable](a S) S {
+	return DedupeBy(a, identity[T])
+}
+
+// DedupeBy returns a new slice with only the first item for each keyfn key (order preserved)
+//
+//	users = filters.DedupeBy(append(fromDB, fromAPI...), func(u User) string { return u.ID })
+func DedupeBy[S ~[]T, T any, K comparable](a S, keyfn func(a T) K) S {
+	seen := make(map[K]struct{}, len(a))
+	return FilterCopy(a, func(a T) bool {
+		k := keyfn(a)
+		if _, ok := seen[k]; ok {
+			return false
+		}
+		seen[k] = struct{}{}
+		return true
+	})
+}
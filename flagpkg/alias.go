@@ -0,0 +1,97 @@
+package flagpkg
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Alias defines more names for the already defined flag name, sharing the same value (works with InverseBoolVar etc).
+//
+//	flag.BoolVar(&verbose, "verbose", false, "verbose output")
+//	flagpkg.Alias("verbose", "v")
+//
+// Panics if name is not defined, or an alias is (like flag.Var redefining).
+// To group aliases on one line in usage output, set flag.Usage = flagpkg.Usage
+func Alias(name string, alias ...string) {
+	commandLine().Alias(name, alias...)
+}
+
+// Alias on s, see the package level Alias
+func (s *FlagSet) Alias(name string, alias ...string) {
+	fs := s.FlagSet
+	f := fs.Lookup(name)
+	if f == nil {
+		panic(fmt.Sprintf("flagpkg: alias for undefined flag %q", name))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.aliases == nil {
+		s.aliases = map[string]string{}
+	}
+	for _, a := range alias {
+		fs.Var(f.Value, a, "alias for -"+name)
+		fs.Lookup(a).DefValue = f.DefValue
+		s.aliases[a] = name
+	}
+}
+
+// Usage is like the default flag.Usage, but with aliases grouped (see Alias and PrintDefaults)
+func Usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", flag.CommandLine.Name())
+	PrintDefaults()
+}
+
+// PrintDefaults is like flag.PrintDefaults, with aliases on the same line, eg: "-verbose, -v"
+func PrintDefaults() {
+	commandLine().PrintDefaults()
+}
+
+// PrintDefaults on s, see the package level PrintDefaults
+func (s *FlagSet) PrintDefaults() {
+	fs := s.FlagSet
+	s.mu.Lock()
+	names := map[string][]string{}
+	isalias := map[string]bool{}
+	for a, name := range s.aliases {
+		names[name] = append(names[name], a)
+		isalias[a] = true
+	}
+	s.mu.Unlock()
+	var b strings.Builder
+	fs.VisitAll(func(f *flag.Flag) {
+		if isalias[f.Name] {
+			return
+		}
+		b.Reset()
+		fmt.Fprintf(&b, "  -%s", f.Name)
+		slices.Sort(names[f.Name])
+		for _, a := range names[f.Name] {
+			fmt.Fprintf(&b, ", -%s", a)
+		}
+		typ, usage := flag.UnquoteUsage(f)
+		if typ != "" {
+			b.WriteString(" " + typ)
+		}
+		b.WriteString("\n    \t")
+		b.WriteString(strings.ReplaceAll(usage, "\n", "\n    \t"))
+		if !isZeroDefault(f) {
+			if typ == "string" {
+				fmt.Fprintf(&b, " (default %q)", f.DefValue)
+			} else {
+				fmt.Fprintf(&b, " (default %v)", f.DefValue)
+			}
+		}
+		fmt.Fprintln(fs.Output(), b.String())
+	})
+}
+
+// isZeroDefault approximates the flag package check (which is unexported)
+func isZeroDefault(f *flag.Flag) bool {
+	switch f.DefValue {
+	case "", "0", "false", "0s", "[]":
+		return true
+	}
+	return false
+}
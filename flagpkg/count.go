@@ -0,0 +1,92 @@
+package flagpkg
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+)
+
+// CountVar defines an int flag that increments each time it is used, eg: "-v -v -v" is 3.
+//
+// "-v=5" sets the count directly. For the "-vvv" form, parse with ExpandCounts:
+//
+//	var verbosity int
+//	flagpkg.CountVar(&verbosity, "v", "verbose (repeat for more)")
+//	flag.CommandLine.Parse(flagpkg.ExpandCounts(os.Args[1:]))
+func CountVar(p *int, name string, usage string) {
+	commandLine().CountVar(p, name, usage)
+}
+
+// CountVar on s, see the package level CountVar
+func (s *FlagSet) CountVar(p *int, name string, usage string) {
+	*p = 0
+	s.FlagSet.Var((*countValue)(p), name, usage)
+}
+
+type countValue int
+
+func (c *countValue) Set(s string) error {
+	if s == "true" {
+		*c++
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	*c = countValue(n)
+	return nil
+}
+
+func (c *countValue) Get() any { return int(*c) }
+
+func (c *countValue) String() string {
+	if c == nil {
+		return "0"
+	}
+	return strconv.Itoa(int(*c))
+}
+
+func (c *countValue) IsBoolFlag() bool { return true }
+
+// ExpandCounts rewrites repeated single-letter count flags ("-vvv") into "-v -v -v".
+//
+// Only flags defined with CountVar are expanded, and nothing after "--".
+func ExpandCounts(args []string) []string {
+	return commandLine().ExpandCounts(args)
+}
+
+// ExpandCounts on s, see the package level ExpandCounts
+func (s *FlagSet) ExpandCounts(args []string) []string {
+	out := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			return append(out, args[i:]...)
+		}
+		if name, ok := repeatedCount(s.FlagSet, arg); ok {
+			for range len(arg) - 1 {
+				out = append(out, "-"+name)
+			}
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// repeatedCount reports the flag name if arg is "-" followed by 2+ of the same count flag letter
+func repeatedCount(fs *flag.FlagSet, arg string) (string, bool) {
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return "", false
+	}
+	name := arg[1:2]
+	if strings.Trim(arg[1:], name) != "" {
+		return "", false
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return "", false
+	}
+	_, ok := f.Value.(*countValue)
+	return name, ok
+}
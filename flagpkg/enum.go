@@ -0,0 +1,47 @@
+package flagpkg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EnumVar defines a string flag that must be one of allowed, checked at parse time.
+//
+// Allowed values are listed in the usage text, eg: "run mode (one of: dev, prod)"
+//
+// The default value is not checked, so empty can mean "not set".
+func EnumVar(p *string, name string, allowed []string, value string, usage string) {
+	commandLine().EnumVar(p, name, allowed, value, usage)
+}
+
+// EnumVar on s, see the package level EnumVar
+func (s *FlagSet) EnumVar(p *string, name string, allowed []string, value string, usage string) {
+	*p = value
+	usage = fmt.Sprintf("%s (one of: %s)", usage, strings.Join(allowed, ", "))
+	s.FlagSet.Var(&enumValue{p: p, allowed: slices.Clone(allowed)}, name, usage)
+}
+
+type enumValue struct {
+	p       *string
+	allowed []string
+}
+
+func (e *enumValue) Set(s string) error {
+	if !slices.Contains(e.allowed, s) {
+		return fmt.Errorf("invalid value %q, expected one of: %s", s, strings.Join(e.allowed, ", "))
+	}
+	*e.p = s
+	return nil
+}
+
+func (e *enumValue) Get() any {
+	return *e.p
+}
+
+func (e *enumValue) String() string {
+	if e == nil || e.p == nil {
+		return ""
+	}
+	return *e.p
+}
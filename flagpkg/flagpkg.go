@@ -1,8 +1,9 @@
-// flagpkg package provides some additional flag functions. (InverseFlagVar)
+// flagpkg package provides some additional flag functions. (InverseFlagVar, DurationVar, StringSliceVar, Struct, ...)
+//
+// Package level functions use flag.CommandLine, see FlagSet for others.
 package flagpkg
 
 import (
-	"flag"
 	"fmt"
 	"strconv"
 )
@@ -17,7 +18,12 @@ import (
 //
 // If multiple flag.BoolVar and InverseBoolVar are used, the last one (on cmdline) wins.
 func InverseBoolVar(p *bool, name string, value bool, usage string) {
-	flag.CommandLine.Var(newBoolValue(value, p), name, usage)
+	commandLine().InverseBoolVar(p, name, value, usage)
+}
+
+// InverseBoolVar on s, see the package level InverseBoolVar
+func (s *FlagSet) InverseBoolVar(p *bool, name string, value bool, usage string) {
+	s.FlagSet.Var(newBoolValue(value, p), name, usage)
 }
 
 // -- inversebool  Value
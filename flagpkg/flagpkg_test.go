@@ -0,0 +1,263 @@
+package flagpkg
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerth/mostly/ncode"
+	"github.com/aerth/mostly/unixtimestamp"
+)
+
+func TestTimeFlags(t *testing.T) {
+	fs := NewFlagSet(t.Name(), flag.ContinueOnError)
+	var (
+		d     time.Duration
+		when  time.Time
+		since unixtimestamp.UnixTimestamp
+	)
+	fs.DurationVar(&d, "test-duration", time.Hour, "")
+	fs.TimeVar(&when, "test-time", time.Time{}, "")
+	fs.UnixTimestampVar(&since, "test-since", time.Time{}, "")
+	if d != time.Hour {
+		t.Fatalf("default not applied: %s", d)
+	}
+	err := fs.Parse([]string{"-test-duration", "1d12h", "-test-time", "2023-11-14", "-test-since", "1700000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 36*time.Hour {
+		t.Fatalf("duration: got %s", d)
+	}
+	if want := time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC); !when.Equal(want) {
+		t.Fatalf("time: got %s", when)
+	}
+	if since.Unix() != 1700000000 {
+		t.Fatalf("since: got %d", since.Unix())
+	}
+	if got := fs.Lookup("test-duration").Value.String(); got != ncode.FormatDuration(36*time.Hour) {
+		t.Fatalf("duration string: %q", got)
+	}
+	if err := fs.Set("test-duration", "nope"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSliceFlags(t *testing.T) {
+	fs := NewFlagSet(t.Name(), flag.ContinueOnError)
+	var headers, execs []string
+	fs.StringSliceVar(&headers, "test-header", []string{"default"}, "")
+	execs = []string{"keep"}
+	fs.Append(&execs, "test-exec", "")
+	err := fs.Parse([]string{"-test-header", "A", "-test-header", "B, C", "-test-exec", "a,b", "-test-exec", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(headers, "|"); got != "A|B|C" {
+		t.Fatalf("headers: %q", got)
+	}
+	if got := strings.Join(execs, "|"); got != "keep|a,b|c" {
+		t.Fatalf("execs: %q", got)
+	}
+}
+
+func TestMapVar(t *testing.T) {
+	fs := NewFlagSet(t.Name(), flag.ContinueOnError)
+	var labels map[string]string
+	fs.MapVar(&labels, "test-label", "")
+	err := fs.Parse([]string{"-test-label", "env=prod", "-test-label", "q=a=b", "-test-label", "env=dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if labels["env"] != "dev" || labels["q"] != "a=b" {
+		t.Fatalf("labels: %v", labels)
+	}
+	if got := fs.Lookup("test-label").Value.String(); got != "env=dev,q=a=b" {
+		t.Fatalf("string: %q", got)
+	}
+	if err := fs.Set("test-label", "novalue"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestEnumVar(t *testing.T) {
+	fs := NewFlagSet(t.Name(), flag.ContinueOnError)
+	var mode string
+	fs.EnumVar(&mode, "test-mode", []string{"dev", "prod"}, "dev", "run mode")
+	if u := fs.Lookup("test-mode").Usage; u != "run mode (one of: dev, prod)" {
+		t.Fatalf("usage: %q", u)
+	}
+	if err := fs.Set("test-mode", "staging"); err == nil {
+		t.Fatal("expected error")
+	}
+	if mode != "dev" {
+		t.Fatalf("mode changed on error: %q", mode)
+	}
+	if err := fs.Set("test-mode", "prod"); err != nil || mode != "prod" {
+		t.Fatalf("mode: %q, %v", mode, err)
+	}
+}
+
+func TestCountVar(t *testing.T) {
+	fs := NewFlagSet(t.Name(), flag.ContinueOnError)
+	var verbosity int
+	fs.CountVar(&verbosity, "x", "")
+	args := fs.ExpandCounts([]string{"-x", "-xxx", "-xy", "--", "-xx"})
+	if got := strings.Join(args, " "); got != "-x -x -x -x -xy -- -xx" {
+		t.Fatalf("expand: %q", got)
+	}
+	if err := fs.Parse([]string{"-x", "-x", "-x"}); err != nil {
+		t.Fatal(err)
+	}
+	if verbosity != 3 {
+		t.Fatalf("count: %d", verbosity)
+	}
+	if err := fs.Set("x", "5"); err != nil || verbosity != 5 {
+		t.Fatalf("count: %d, %v", verbosity, err)
+	}
+}
+
+func TestStruct(t *testing.T) {
+	fs := NewFlagSet(t.Name(), flag.ContinueOnError)
+	type TLS struct {
+		Cert string `usage:"cert file"`
+	}
+	var cfg struct {
+		httpserverConfig
+		Addr    string        `flag:"test-addr" default:":8080" env:"TEST_ADDR"`
+		Timeout time.Duration `flag:"test-timeout" default:"1d"`
+		Debug   bool          `flag:"test-debug"`
+		Tags    []string      `flag:"test-tags" default:"a,b"`
+		Skip    int           `flag:"-"`
+		TLS     TLS           `flag:"test-tls"`
+	}
+	t.Setenv("TEST_ADDR", ":9090")
+	if err := fs.Struct(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if f := fs.Lookup("test-addr"); f.DefValue != ":8080" || cfg.Addr != ":9090" || f.Usage != "(env TEST_ADDR)" {
+		t.Fatalf("addr: %q default %q usage %q", cfg.Addr, f.DefValue, f.Usage)
+	}
+	if cfg.Timeout != 24*time.Hour {
+		t.Fatalf("timeout: %s", cfg.Timeout)
+	}
+	err := fs.Parse([]string{"-test-debug", "-test-tags", "c", "-test-tls-cert", "x.pem", "-base-url", "http://x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Debug || strings.Join(cfg.Tags, ",") != "c" || cfg.TLS.Cert != "x.pem" || cfg.BaseURL != "http://x" {
+		t.Fatalf("parsed: %+v", cfg)
+	}
+	if err := fs.Struct(cfg); err == nil {
+		t.Fatal("expected error for non-pointer")
+	}
+}
+
+// httpserverConfig like httpserver.Config
+type httpserverConfig struct {
+	BaseURL string `json:"base_url"`
+}
+
+func TestSecretVar(t *testing.T) {
+	fs := NewFlagSet(t.Name(), flag.ContinueOnError)
+	var token string
+	fs.SecretVar(&token, "test-token", "", "")
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_TOKEN", "from-env")
+	for arg, want := range map[string]string{"direct": "direct", "@" + path: "from-file", "env:TEST_TOKEN": "from-env"} {
+		if err := fs.Set("test-token", arg); err != nil {
+			t.Fatal(err)
+		}
+		if token != want {
+			t.Fatalf("%s: got %q", arg, token)
+		}
+		if s := fs.Lookup("test-token").Value.String(); s != Redacted {
+			t.Fatalf("not redacted: %q", s)
+		}
+	}
+	if err := fs.Set("test-token", "env:TEST_TOKEN_MISSING"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestAlias(t *testing.T) {
+	fs := NewFlagSet(t.Name(), flag.ContinueOnError)
+	var verbose, color bool
+	fs.BoolVar(&verbose, "test-verbose", false, "verbose output")
+	fs.Alias("test-verbose", "test-vv")
+	fs.InverseBoolVar(&color, "test-no-color", true, "disable color")
+	fs.Alias("test-no-color", "test-nc")
+	if err := fs.Parse([]string{"-test-vv", "-test-nc"}); err != nil {
+		t.Fatal(err)
+	}
+	if !verbose || color {
+		t.Fatalf("verbose=%v color=%v", verbose, color)
+	}
+	var buf strings.Builder
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	if !strings.Contains(buf.String(), "  -test-verbose, -test-vv\n    \tverbose output\n") {
+		t.Fatalf("usage not grouped:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "alias for") {
+		t.Fatalf("alias printed separately:\n%s", buf.String())
+	}
+}
+
+func TestGroups(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"-syslog"}, true},
+		{[]string{"-syslog", "-j"}, false},
+		{[]string{"-tls-cert", "x"}, true},
+		{[]string{"-tls-key", "x"}, false},
+		{[]string{"-tls-key", "x", "-tls-cert", "y"}, true},
+	} {
+		fs := NewFlagSet("test", flag.ContinueOnError)
+		fs.Bool("syslog", false, "")
+		fs.Bool("journald", false, "")
+		fs.String("tls-key", "", "")
+		fs.String("tls-cert", "", "")
+		fs.Alias("journald", "j")
+		fs.Exclusive("syslog", "journald")
+		fs.Requires("tls-key", "tls-cert")
+		if err := fs.Parse(tc.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.Validate(); (err == nil) != tc.ok {
+			t.Fatalf("%v: got %v", tc.args, err)
+		}
+	}
+}
+
+func TestFlagSet(t *testing.T) {
+	fs := NewFlagSet("sub", flag.ContinueOnError)
+	var (
+		d       time.Duration
+		v       int
+		headers []string
+		quiet   bool
+	)
+	fs.DurationVar(&d, "timeout", time.Minute, "")
+	fs.CountVar(&v, "v", "")
+	fs.StringSliceVar(&headers, "header", nil, "")
+	fs.InverseBoolVar(&quiet, "loud", true, "")
+	fs.Alias("header", "H")
+	if err := fs.Parse(fs.ExpandCounts([]string{"-timeout", "2d", "-vv", "-H", "a,b", "-loud"})); err != nil {
+		t.Fatal(err)
+	}
+	if d != 48*time.Hour || v != 2 || len(headers) != 2 || quiet {
+		t.Fatalf("d=%s v=%d headers=%v quiet=%v", d, v, headers, quiet)
+	}
+	if flag.Lookup("timeout") != nil || flag.Lookup("H") != nil {
+		t.Fatal("defined on flag.CommandLine")
+	}
+}
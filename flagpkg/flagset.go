@@ -0,0 +1,50 @@
+package flagpkg
+
+import (
+	"flag"
+	"sync"
+)
+
+// FlagSet has all the flagpkg functions as methods, for subcommands and libraries
+// that should not define flags on flag.CommandLine.
+//
+//	fs := flagpkg.NewFlagSet("serve", flag.ExitOnError)
+//	fs.DurationVar(&timeout, "timeout", time.Minute, "request timeout")
+//	fs.Alias("timeout", "t")
+//	fs.Parse(args)
+//
+// Note: DurationVar and PrintDefaults replace the flag.FlagSet methods of the same name.
+type FlagSet struct {
+	*flag.FlagSet
+
+	mu      sync.Mutex
+	aliases map[string]string // alias -> name, see Alias
+	groups  []constraint      // see Exclusive and Requires
+}
+
+// NewFlagSet like flag.NewFlagSet
+func NewFlagSet(name string, errorHandling flag.ErrorHandling) *FlagSet {
+	return &FlagSet{FlagSet: flag.NewFlagSet(name, errorHandling)}
+}
+
+// Wrap an existing flag.FlagSet, flags defined on either are shared.
+//
+// Aliases and groups are kept in the returned FlagSet (not in fs), so wrap once and keep it.
+func Wrap(fs *flag.FlagSet) *FlagSet {
+	return &FlagSet{FlagSet: fs}
+}
+
+var (
+	cmdlinemu sync.Mutex
+	cmdline   *FlagSet
+)
+
+// commandLine is the package level FlagSet, wrapped again if flag.CommandLine was replaced
+func commandLine() *FlagSet {
+	cmdlinemu.Lock()
+	defer cmdlinemu.Unlock()
+	if cmdline == nil || cmdline.FlagSet != flag.CommandLine {
+		cmdline = Wrap(flag.CommandLine)
+	}
+	return cmdline
+}
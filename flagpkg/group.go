@@ -0,0 +1,87 @@
+package flagpkg
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+type constraint struct {
+	exclusive bool
+	name      string   // Requires only
+	names     []string // Exclusive: any two, Requires: all needed
+}
+
+// Exclusive declares that at most one of the flags may be set on cmdline (checked by Validate)
+//
+//	flagpkg.Exclusive("syslog", "journald")
+func Exclusive(names ...string) {
+	commandLine().Exclusive(names...)
+}
+
+// Exclusive on s, see the package level Exclusive
+func (s *FlagSet) Exclusive(names ...string) {
+	s.addConstraint(constraint{exclusive: true, names: names})
+}
+
+// Requires declares that if flag name is set on cmdline, all of the other flags must be too (checked by Validate)
+//
+//	flagpkg.Requires("tls-key", "tls-cert")
+//	flagpkg.Requires("tls-cert", "tls-key")
+func Requires(name string, requires ...string) {
+	commandLine().Requires(name, requires...)
+}
+
+// Requires on s, see the package level Requires
+func (s *FlagSet) Requires(name string, requires ...string) {
+	s.addConstraint(constraint{name: name, names: requires})
+}
+
+func (s *FlagSet) addConstraint(c constraint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups = append(s.groups, c)
+}
+
+// Validate checks Exclusive and Requires groups against flags set on cmdline, call after flag.Parse
+//
+// Aliases (see Alias) count as their flag.
+func Validate() error {
+	return commandLine().Validate()
+}
+
+// Validate on s, see the package level Validate
+func (s *FlagSet) Validate() error {
+	set := map[string]bool{}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.FlagSet.Visit(func(f *flag.Flag) {
+		if name, ok := s.aliases[f.Name]; ok {
+			set[name] = true
+		}
+		set[f.Name] = true
+	})
+	for _, c := range s.groups {
+		if c.exclusive {
+			var used []string
+			for _, name := range c.names {
+				if set[name] {
+					used = append(used, "-"+name)
+				}
+			}
+			if len(used) > 1 {
+				return fmt.Errorf("flags %s can not be used together", strings.Join(used, ", "))
+			}
+			continue
+		}
+		if !set[c.name] {
+			continue
+		}
+		for _, name := range c.names {
+			if !set[name] {
+				return fmt.Errorf("flag -%s requires -%s", c.name, name)
+			}
+		}
+	}
+	return nil
+}
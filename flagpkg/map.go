@@ -0,0 +1,53 @@
+package flagpkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MapVar defines a repeatable key=value flag collected into a map.
+//
+// For example, "--label env=prod --label team=infra" would set m["env"] and m["team"].
+//
+// A nil *p is allocated. Later keys overwrite earlier ones (and defaults).
+func MapVar(p *map[string]string, name string, usage string) {
+	commandLine().MapVar(p, name, usage)
+}
+
+// MapVar on s, see the package level MapVar
+func (s *FlagSet) MapVar(p *map[string]string, name string, usage string) {
+	if *p == nil {
+		*p = map[string]string{}
+	}
+	s.FlagSet.Var(&mapValue{p: p}, name, usage)
+}
+
+type mapValue struct {
+	p *map[string]string
+}
+
+func (m *mapValue) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("expected key=value: %q", s)
+	}
+	(*m.p)[k] = v
+	return nil
+}
+
+func (m *mapValue) Get() any {
+	return *m.p
+}
+
+func (m *mapValue) String() string {
+	if m == nil || m.p == nil || len(*m.p) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(*m.p))
+	for k, v := range *m.p {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
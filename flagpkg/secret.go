@@ -0,0 +1,59 @@
+package flagpkg
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Redacted is printed instead of a non-empty secret flag value
+var Redacted = "********"
+
+// SecretVar defines a string flag for tokens and passwords, which is never printed (usage, String).
+//
+// The value can be given directly, as "@/path/to/file" (trailing newline trimmed), or as "env:NAME":
+//
+//	--db-password=@/run/secrets/db
+//	--api-token=env:API_TOKEN
+//
+// Direct values are visible in the process list, prefer file or env.
+func SecretVar(p *string, name string, value string, usage string) {
+	commandLine().SecretVar(p, name, value, usage)
+}
+
+// SecretVar on s, see the package level SecretVar
+func (s *FlagSet) SecretVar(p *string, name string, value string, usage string) {
+	*p = value
+	s.FlagSet.Var((*secretValue)(p), name, usage)
+}
+
+type secretValue string
+
+func (s *secretValue) Set(v string) error {
+	switch {
+	case strings.HasPrefix(v, "@"):
+		b, err := os.ReadFile(v[1:])
+		if err != nil {
+			return err
+		}
+		v = strings.TrimRight(string(b), "\r\n")
+	case strings.HasPrefix(v, "env:"):
+		name := v[len("env:"):]
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return fmt.Errorf("environment variable %q not set", name)
+		}
+		v = val
+	}
+	*s = secretValue(v)
+	return nil
+}
+
+func (s *secretValue) Get() any { return string(*s) }
+
+func (s *secretValue) String() string {
+	if s == nil || *s == "" {
+		return ""
+	}
+	return Redacted
+}
@@ -0,0 +1,74 @@
+package flagpkg
+
+import (
+	"strings"
+)
+
+// StringSliceVar defines a repeatable []string flag, each value may also be comma-separated.
+//
+// For example, "--header A --header B,C" would set to []string{"A", "B", "C"}.
+//
+// The first use on cmdline replaces the default value, later uses append.
+func StringSliceVar(p *[]string, name string, value []string, usage string) {
+	commandLine().StringSliceVar(p, name, value, usage)
+}
+
+// StringSliceVar on s, see the package level StringSliceVar
+func (s *FlagSet) StringSliceVar(p *[]string, name string, value []string, usage string) {
+	s.FlagSet.Var(newSliceValue(value, p, ","), name, usage)
+}
+
+// Append defines a repeatable []string flag without comma splitting, for values that may contain commas.
+//
+// For example, "--exec 'a,b' --exec c" would append "a,b" and "c" to p.
+//
+// Unlike StringSliceVar, existing values in p are kept.
+func Append(p *[]string, name string, usage string) {
+	commandLine().Append(p, name, usage)
+}
+
+// Append on s, see the package level Append
+func (s *FlagSet) Append(p *[]string, name string, usage string) {
+	v := newSliceValue(*p, p, "")
+	v.changed = true // keep existing
+	s.FlagSet.Var(v, name, usage)
+}
+
+type sliceValue struct {
+	p       *[]string
+	sep     string // empty is no splitting
+	changed bool
+}
+
+func newSliceValue(value []string, p *[]string, sep string) *sliceValue {
+	*p = append([]string(nil), value...)
+	return &sliceValue{p: p, sep: sep}
+}
+
+func (s *sliceValue) Set(v string) error {
+	if !s.changed {
+		*s.p = nil
+		s.changed = true
+	}
+	if s.sep == "" {
+		*s.p = append(*s.p, v)
+		return nil
+	}
+	for _, part := range strings.Split(v, s.sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			*s.p = append(*s.p, part)
+		}
+	}
+	return nil
+}
+
+func (s *sliceValue) Get() any {
+	return *s.p
+}
+
+func (s *sliceValue) String() string {
+	if s == nil || s.p == nil {
+		return ""
+	}
+	return strings.Join(*s.p, ",")
+}
@@ -0,0 +1,139 @@
+package flagpkg
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Struct defines flags for each exported field of the struct pointed to by v.
+//
+// Tags (all optional):
+//
+//	flag:"name"      flag name ("-" to skip), defaults to the json tag name (with "_" as "-") or the lowercase field name
+//	default:"value"  default value, parsed like the cmdline value, otherwise the current field value is the default
+//	usage:"text"     usage text
+//	env:"NAME"       environment variable that overrides the default (cmdline still wins)
+//
+// Supported fields: bool, int, int64, uint, uint64, float64, string, time.Duration (extended units),
+// time.Time, []string (see StringSliceVar), anything implementing flag.Value, and nested structs
+// (flag names prefixed with "parent-", embedded structs are not prefixed).
+//
+//	var cfg struct {
+//		Addr    string        `flag:"addr" default:":8080" usage:"listen address" env:"ADDR"`
+//		Timeout time.Duration `default:"30s"`
+//	}
+//	if err := flagpkg.Struct(&cfg); err != nil { ... }
+//	flag.Parse()
+func Struct(v any) error {
+	return commandLine().Struct(v)
+}
+
+// Struct on s, see the package level Struct
+func (s *FlagSet) Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("flagpkg: expected pointer to struct, got %T", v)
+	}
+	return structFlags(s.FlagSet, rv.Elem(), "")
+}
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	timeType     = reflect.TypeFor[time.Time]()
+	valueType    = reflect.TypeFor[flag.Value]()
+)
+
+func structFlags(fs *flag.FlagSet, rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+		name := fieldFlagName(field)
+		if name == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+		if field.Type.Kind() == reflect.Struct && field.Type != timeType && !fv.Addr().Type().Implements(valueType) {
+			nested := prefix
+			if !field.Anonymous {
+				nested = prefix + name + "-"
+			}
+			if err := structFlags(fs, fv, nested); err != nil {
+				return err
+			}
+			continue
+		}
+		name = prefix + name
+		p := fv.Addr().Interface()
+		usage := field.Tag.Get("usage")
+		env := field.Tag.Get("env")
+		if env != "" {
+			usage = strings.TrimSpace(usage + " (env " + env + ")")
+		}
+		switch {
+		case fv.Addr().Type().Implements(valueType):
+			fs.Var(p.(flag.Value), name, usage)
+		case field.Type == durationType:
+			fs.Var((*durationValue)(p.(*time.Duration)), name, usage)
+		case field.Type == timeType:
+			fs.Var((*timeValue)(p.(*time.Time)), name, usage)
+		default:
+			switch p := p.(type) {
+			case *bool:
+				fs.BoolVar(p, name, *p, usage)
+			case *int:
+				fs.IntVar(p, name, *p, usage)
+			case *int64:
+				fs.Int64Var(p, name, *p, usage)
+			case *uint:
+				fs.UintVar(p, name, *p, usage)
+			case *uint64:
+				fs.Uint64Var(p, name, *p, usage)
+			case *float64:
+				fs.Float64Var(p, name, *p, usage)
+			case *string:
+				fs.StringVar(p, name, *p, usage)
+			case *[]string:
+				fs.Var(newSliceValue(*p, p, ","), name, usage)
+			default:
+				return fmt.Errorf("flagpkg: field %s: unsupported type %s", field.Name, field.Type)
+			}
+		}
+		f := fs.Lookup(name)
+		if def, ok := field.Tag.Lookup("default"); ok {
+			if err := f.Value.Set(def); err != nil {
+				return fmt.Errorf("flagpkg: field %s: bad default %q: %w", field.Name, def, err)
+			}
+		}
+		f.DefValue = f.Value.String()
+		if val, ok := os.LookupEnv(env); ok && env != "" {
+			if err := f.Value.Set(val); err != nil {
+				return fmt.Errorf("flagpkg: field %s: bad $%s %q: %w", field.Name, env, val, err)
+			}
+		}
+		if sv, ok := f.Value.(*sliceValue); ok {
+			sv.changed = false // cmdline replaces default/env
+		}
+	}
+	return nil
+}
+
+// fieldFlagName from the flag tag, json tag or field name
+func fieldFlagName(field reflect.StructField) string {
+	if name := field.Tag.Get("flag"); name != "" {
+		return name
+	}
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		if name == "-" {
+			return strings.ToLower(field.Name) // json skip is not flag skip
+		}
+		return strings.ReplaceAll(name, "_", "-")
+	}
+	return strings.ToLower(field.Name)
+}
@@ -0,0 +1,78 @@
+package flagpkg
+
+import (
+	"time"
+
+	"github.com/aerth/mostly/ncode"
+	"github.com/aerth/mostly/unixtimestamp"
+)
+
+// DurationVar defines a time.Duration flag with extended units (see ncode.ParseDuration), eg: "2d", "1w", "90m"
+func DurationVar(p *time.Duration, name string, value time.Duration, usage string) {
+	commandLine().DurationVar(p, name, value, usage)
+}
+
+// DurationVar on s, see the package level DurationVar
+func (s *FlagSet) DurationVar(p *time.Duration, name string, value time.Duration, usage string) {
+	*p = value
+	s.FlagSet.Var((*durationValue)(p), name, usage)
+}
+
+type durationValue time.Duration
+
+func (d *durationValue) Set(s string) error {
+	v, err := ncode.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = durationValue(v)
+	return nil
+}
+
+func (d *durationValue) Get() any { return time.Duration(*d) }
+
+func (d *durationValue) String() string { return ncode.FormatDuration(time.Duration(*d)) }
+
+// TimeVar defines a time.Time flag accepting unix seconds, RFC3339 or a date (see unixtimestamp.ParseFlag)
+//
+// Default value prints as RFC3339, zero time prints as empty.
+func TimeVar(p *time.Time, name string, value time.Time, usage string) {
+	commandLine().TimeVar(p, name, value, usage)
+}
+
+// TimeVar on s, see the package level TimeVar
+func (s *FlagSet) TimeVar(p *time.Time, name string, value time.Time, usage string) {
+	*p = value
+	s.FlagSet.Var((*timeValue)(p), name, usage)
+}
+
+type timeValue time.Time
+
+func (t *timeValue) Set(s string) error {
+	v, err := unixtimestamp.ParseFlag(s)
+	if err != nil {
+		return err
+	}
+	*t = timeValue(v)
+	return nil
+}
+
+func (t *timeValue) Get() any { return time.Time(*t) }
+
+func (t *timeValue) String() string {
+	if t == nil || time.Time(*t).IsZero() {
+		return ""
+	}
+	return time.Time(*t).Format(time.RFC3339)
+}
+
+// UnixTimestampVar defines a unixtimestamp.UnixTimestamp flag (see unixtimestamp.ParseFlag)
+func UnixTimestampVar(p *unixtimestamp.UnixTimestamp, name string, value time.Time, usage string) {
+	commandLine().UnixTimestampVar(p, name, value, usage)
+}
+
+// UnixTimestampVar on s, see the package level UnixTimestampVar
+func (s *FlagSet) UnixTimestampVar(p *unixtimestamp.UnixTimestamp, name string, value time.Time, usage string) {
+	p.Time = value
+	s.FlagSet.Var(p, name, usage)
+}
@@ -1,14 +1,21 @@
 module github.com/aerth/mostly
 
-go 1.22
+go 1.23.0
 
-toolchain go1.22.5
-
-require go.etcd.io/bbolt v1.3.11
+require (
+	github.com/fxamacker/cbor/v2 v2.9.4
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	go.etcd.io/bbolt v1.3.11
+	golang.org/x/crypto v0.26.0
+	google.golang.org/protobuf v1.34.2
+)
 
 require (
-	golang.org/x/sync v0.8.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/net v0.21.0 // indirect
 	golang.org/x/sys v0.24.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
 )
 
-retract v0.0.5 // unixtimestamp sql issue, fixed in v0.0.6
\ No newline at end of file
+retract v0.0.5 // unixtimestamp sql issue, fixed in v0.0.6
@@ -0,0 +1,139 @@
+package httpserver
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aerth/mostly/httpserver/httpctx"
+)
+
+// LogFormat for EnableAccessLog
+type LogFormat int
+
+const (
+	LogCommon   LogFormat = iota // Common Log Format, followed by request id and latency
+	LogCombined                  // LogCommon plus "referer" "user-agent", followed by request id and latency
+	LogJSON                      // one JSON object per line
+)
+
+// EnableAccessLog writes one line per request to w (nil disables), installed by ListenAndServeAll.
+//
+// Lines include the httpctx request id (GetUUID), client ip (GetClientIP), basic auth user, status, bytes written and latency.
+//
+//	127.0.0.1 - alice [02/Jan/2006:15:04:05 -0700] "GET /x HTTP/1.1" 200 512 1042 1.2ms
+func (s *HttpServer) EnableAccessLog(w io.Writer, format LogFormat) {
+	if w == nil {
+		s.accesslog = nil
+		return
+	}
+	s.accesslog = &accessLog{w: w, format: format}
+}
+
+type accessLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format LogFormat
+}
+
+// accessLogEntry is the LogJSON line
+type accessLogEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID int       `json:"request_id"`
+	ClientIP  string    `json:"client_ip"`
+	User      string    `json:"user,omitempty"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Proto     string    `json:"proto"`
+	Status    int       `json:"status"`
+	Bytes     int64     `json:"bytes"`
+	LatencyMS float64   `json:"latency_ms"`
+	Referer   string    `json:"referer,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+}
+
+func (l *accessLog) log(r *http.Request, rec *responseRecorder) {
+	ctx := r.Context()
+	e := accessLogEntry{
+		Time:      httpctx.GetStart(ctx),
+		RequestID: httpctx.GetUUID(ctx),
+		ClientIP:  httpctx.GetClientIP(r).String(),
+		Method:    r.Method,
+		Path:      r.URL.RequestURI(),
+		Proto:     r.Proto,
+		Status:    cmp.Or(rec.status, http.StatusOK), // nothing written
+		Bytes:     rec.bytes,
+		Referer:   r.Referer(),
+		UserAgent: r.UserAgent(),
+	}
+	latency := httpctx.Elapsed(ctx)
+	e.LatencyMS = float64(latency.Microseconds()) / 1000
+	if user, _, ok := r.BasicAuth(); ok {
+		e.User = user
+	}
+	var line []byte
+	if l.format == LogJSON {
+		line, _ = json.Marshal(e)
+	} else {
+		user := e.User
+		if user == "" {
+			user = "-"
+		}
+		line = fmt.Appendf(nil, "%s - %s [%s] %q %d %d", e.ClientIP, user, e.Time.Format("02/Jan/2006:15:04:05 -0700"),
+			e.Method+" "+e.Path+" "+e.Proto, e.Status, e.Bytes)
+		if l.format == LogCombined {
+			line = fmt.Appendf(line, " %q %q", e.Referer, e.UserAgent)
+		}
+		line = fmt.Appendf(line, " %d %s", e.RequestID, latency.Round(time.Microsecond))
+	}
+	line = append(line, '\n')
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(line)
+}
+
+// responseRecorder counts status and bytes written
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseRecorder) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap for http.ResponseController
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseRecorder) Flush() {
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
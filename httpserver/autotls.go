@@ -0,0 +1,56 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ListenAndServeAutoTLS is ListenAndServeAll with certificates from Let's Encrypt (ACME), instead of cert/key files.
+//
+// Certificates for domains are requested on first use and renewed automatically, stored in cacheDir (keep it private, persistent).
+// Plain http requests on httpAddr answer ACME challenges and redirect to https; httpAddr may be empty (TLS-ALPN challenge only).
+// Issued and renewed certificates are logged to ErrorLog. By using this, you accept the Let's Encrypt terms of service.
+// After Refresh, call ListenAndServeAutoTLS again (not ListenAndServeAll) to keep using it.
+func (s *HttpServer) ListenAndServeAutoTLS(httpAddr, httpsAddr string, domains []string, cacheDir string) error {
+	if s.Err() != nil {
+		return fmt.Errorf("httpserver: already cancelled: %v", s.Err())
+	}
+	if httpsAddr == "" {
+		return fmt.Errorf("httpserver: autotls: no httpsAddr")
+	}
+	if len(domains) == 0 {
+		return fmt.Errorf("httpserver: autotls: no domains")
+	}
+	if cacheDir == "" {
+		return fmt.Errorf("httpserver: autotls: no cache dir")
+	}
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      &logCache{Cache: autocert.DirCache(cacheDir), log: s.ErrorLog},
+	}
+	s.Server.TLSConfig = m.TLSConfig()
+	s.autotls = m
+	return s.ListenAndServeAll(httpAddr, httpsAddr, "", "")
+}
+
+// logCache logs what autocert stores: issued or renewed certificates (and the account key once)
+type logCache struct {
+	autocert.Cache
+	log *log.Logger
+}
+
+func (c *logCache) Put(ctx context.Context, name string, data []byte) error {
+	err := c.Cache.Put(ctx, name, data)
+	if c.log != nil {
+		if err != nil {
+			c.log.Printf("autotls: storing %s: %v", name, err)
+		} else {
+			c.log.Printf("autotls: stored %s", name)
+		}
+	}
+	return err
+}
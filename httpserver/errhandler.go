@@ -0,0 +1,70 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aerth/mostly/httpserver/httpctx"
+	"github.com/aerth/mostly/stackerr"
+)
+
+// HTTPErrorLog for ServeHTTPError, nil uses slog.Default()
+var HTTPErrorLog *slog.Logger
+
+// HandlerFunc is an http handler returning an error, see ServeHTTPError
+//
+//	mux.Handle("/user", httpserver.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error { ... }))
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls fn, errors (and recovered panics) are passed to ServeHTTPError.
+//
+// http.ErrAbortHandler panics are passed on. If fn already wrote the header, the error is only logged.
+func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &responseRecorder{ResponseWriter: w}
+	defer func() {
+		p := recover()
+		if p == http.ErrAbortHandler {
+			panic(p)
+		}
+		if err := stackerr.Recover(p); err != nil {
+			serveHTTPError(rec, r, err, rec.status == 0)
+		}
+	}()
+	if err := fn(rec, r); err != nil {
+		serveHTTPError(rec, r, err, rec.status == 0)
+	}
+}
+
+// ServeHTTPError logs the whole chain (with request ID) and writes {"code":404,"error":"..."} with stackerr.ClientMessage.
+//
+// 5xx are logged at error level, others at info level.
+func ServeHTTPError(w http.ResponseWriter, r *http.Request, err error) {
+	serveHTTPError(w, r, err, true)
+}
+
+func serveHTTPError(w http.ResponseWriter, r *http.Request, err error, respond bool) {
+	code := stackerr.HTTPStatus(err)
+	logger := HTTPErrorLog
+	if logger == nil {
+		logger = slog.Default()
+	}
+	level := slog.LevelInfo
+	if code >= 500 {
+		level = slog.LevelError
+	}
+	logger.Log(r.Context(), level, "http error",
+		slog.Int("request_id", httpctx.GetUUID(r.Context())),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", code),
+		slog.Any("err", err),
+		slog.Bool("responded", respond),
+	)
+	if !respond {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{"code": code, "error": stackerr.ClientMessage(err)})
+}
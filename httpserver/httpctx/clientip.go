@@ -0,0 +1,119 @@
+package httpctx
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+)
+
+var trustedProxies atomic.Pointer[[]netip.Prefix]
+
+// SetTrustedProxies sets the proxies (IPs or CIDRs, eg: "10.0.0.0/8", "127.0.0.1") whose
+// Forwarded and X-Forwarded-For headers are used by GetClientIP. None (default) ignores the headers.
+func SetTrustedProxies(proxies ...string) error {
+	var prefixes []netip.Prefix
+	for _, s := range proxies {
+		if !strings.Contains(s, "/") {
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return err
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	trustedProxies.Store(&prefixes)
+	return nil
+}
+
+func isTrusted(addr netip.Addr) bool {
+	p := trustedProxies.Load()
+	if p == nil {
+		return false
+	}
+	for _, prefix := range *p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRemoteIP returns the IP of the connection stored in ctx (see KConn), without looking at headers
+func GetRemoteIP(ctx context.Context) netip.Addr {
+	if c := GetConn(ctx); c != nil {
+		return parseIP(c.RemoteAddr().String())
+	}
+	return netip.Addr{}
+}
+
+// GetClientIP returns the client IP for logging and rate limiting.
+//
+// It is the connection's remote IP, unless that is a trusted proxy (see SetTrustedProxies): then the
+// Forwarded (or X-Forwarded-For) header is read right to left, and the first untrusted address wins.
+// The zero netip.Addr is returned if nothing could be parsed.
+func GetClientIP(r *http.Request) netip.Addr {
+	peer := GetRemoteIP(r.Context())
+	if !peer.IsValid() {
+		peer = parseIP(r.RemoteAddr)
+	}
+	if !peer.IsValid() || !isTrusted(peer) {
+		return peer
+	}
+	hops := forwardedFor(r.Header)
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr := parseIP(hops[i])
+		if !addr.IsValid() {
+			break // garbage, can't trust anything further left
+		}
+		peer = addr
+		if !isTrusted(addr) {
+			break
+		}
+	}
+	return peer
+}
+
+// forwardedFor returns the client chain from the Forwarded header (RFC 7239), or X-Forwarded-For
+func forwardedFor(h http.Header) []string {
+	var hops []string
+	for _, line := range h.Values("Forwarded") {
+		for _, elem := range strings.Split(line, ",") {
+			for _, pair := range strings.Split(elem, ";") {
+				k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok && strings.EqualFold(k, "for") {
+					hops = append(hops, strings.Trim(v, `"`))
+				}
+			}
+		}
+	}
+	if len(hops) != 0 {
+		return hops
+	}
+	for _, line := range h.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(line, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	return hops
+}
+
+// parseIP from "1.2.3.4", "1.2.3.4:80", "[::1]:80" or "[::1]"
+func parseIP(s string) netip.Addr {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(strings.Trim(s, "[]"))
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
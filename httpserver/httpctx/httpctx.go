@@ -4,7 +4,9 @@ package httpctx
 import (
 	"context"
 	"crypto/tls"
+	"log/slog"
 	"net"
+	"time"
 )
 
 type contextKey string
@@ -12,6 +14,8 @@ type contextKey string
 const KListener contextKey = "listener" // for assigning listener to context
 const KUUID contextKey = "uuid"         // for assigning UUID (RequestID) to context
 const KConn contextKey = "conn"         // for assigning net.Conn to context
+const KLogger contextKey = "logger"     // for assigning a request scoped *slog.Logger to context
+const KStart contextKey = "start"       // for assigning request start time.Time to context
 
 // GetUUID returns unique Request ID for this request (not user ID)
 func GetUUID(ctx context.Context) int {
@@ -75,3 +79,27 @@ func GetAny[T any](ctx context.Context, tag any) (T, bool) {
 	var v T // if ptr, nil
 	return v, false
 }
+
+// GetLogger returns the request scoped logger (see superlog.RequestLoggerMiddleware), or slog.Default()
+func GetLogger(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(KLogger).(*slog.Logger); ok && l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// GetStart returns the time the server started handling this request (zero if not set)
+func GetStart(ctx context.Context) time.Time {
+	if v, ok := ctx.Value(KStart).(time.Time); ok {
+		return v
+	}
+	return time.Time{}
+}
+
+// Elapsed since GetStart (zero if not set), eg: for latency in deferred logging
+func Elapsed(ctx context.Context) time.Duration {
+	if start := GetStart(ctx); !start.IsZero() {
+		return time.Since(start)
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package httpctx
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	k1 := NewKey[string]("name")
+	k2 := NewKey[string]("name")
+	ctx := Set(context.Background(), k1, "one")
+	if v, ok := k1.Get(ctx); !ok || v != "one" {
+		t.Fatalf("Get: %q %v", v, ok)
+	}
+	if _, ok := k2.Get(ctx); ok {
+		t.Fatal("keys with same name collided")
+	}
+	if v, ok := GetAny[string](ctx, k1); !ok || v != "one" {
+		t.Fatalf("GetAny: %q %v", v, ok)
+	}
+}
+
+func TestGetClientIP(t *testing.T) {
+	defer SetTrustedProxies()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.2:1234"
+	r.Header.Set("X-Forwarded-For", "1.1.1.1, 203.0.113.9, 10.0.0.3")
+	if ip := GetClientIP(r); ip.String() != "10.0.0.2" {
+		t.Fatalf("untrusted proxy headers used: %s", ip)
+	}
+	if err := SetTrustedProxies("10.0.0.0/8"); err != nil {
+		t.Fatal(err)
+	}
+	if ip := GetClientIP(r); ip.String() != "203.0.113.9" {
+		t.Fatalf("xff: %s", ip)
+	}
+	r.Header.Set("Forwarded", `for=192.0.2.60;proto=http, for="[2001:db8::1]:4711"`)
+	if ip := GetClientIP(r); ip.String() != "2001:db8::1" {
+		t.Fatalf("forwarded: %s", ip)
+	}
+	if err := SetTrustedProxies("nope"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestStore(t *testing.T) {
+	if _, ok := StoreGet[string](context.Background(), "k"); ok {
+		t.Fatal("nil store returned value")
+	}
+	Store(context.Background()).Set("k", "dropped") // no panic
+	ctx := WithStore(context.Background())
+	Store(ctx).Set("k", "v")
+	if v, ok := StoreGet[string](ctx, "k"); !ok || v != "v" {
+		t.Fatalf("StoreGet: %q %v", v, ok)
+	}
+	if _, ok := StoreGet[int](ctx, "k"); ok {
+		t.Fatal("wrong type returned ok")
+	}
+	Store(ctx).Delete("k")
+	if _, ok := Store(ctx).Get("k"); ok {
+		t.Fatal("not deleted")
+	}
+}
+
+func TestPrincipal(t *testing.T) {
+	outer := WithStore(context.Background())
+	inner := SetPrincipal(outer, &Principal{ID: "u1", Roles: []string{"admin"}, Method: "bearer"})
+	p, ok := GetPrincipal[*Principal](inner)
+	if !ok || p.ID != "u1" || !p.HasRole("admin") || p.HasRole("root") {
+		t.Fatalf("GetPrincipal: %+v %v", p, ok)
+	}
+	Store(outer).Set("principal", &Principal{ID: "root"})
+	if _, ok := GetPrincipal[*Principal](outer); ok {
+		t.Fatal("principal from store")
+	}
+	if _, ok := GetPrincipal[string](inner); ok {
+		t.Fatal("wrong type returned ok")
+	}
+	var none *Principal
+	if none.HasRole("admin") {
+		t.Fatal("nil principal has role")
+	}
+}
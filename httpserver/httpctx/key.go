@@ -0,0 +1,32 @@
+package httpctx
+
+import "context"
+
+// Key is a typed context key, see NewKey. Each NewKey is unique even with the same name.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new typed context key, name is only for debugging
+//
+//	var KTenant = httpctx.NewKey[*Tenant]("tenant")
+//	ctx = httpctx.Set(ctx, KTenant, tenant)
+//	tenant, ok := KTenant.Get(ctx)
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the key name
+func (k *Key[T]) String() string {
+	return "httpctx.Key(" + k.name + ")"
+}
+
+// Get the value set with Set (false if not set)
+func (k *Key[T]) Get(ctx context.Context) (T, bool) {
+	return GetAny[T](ctx, k)
+}
+
+// Set returns a child context with v stored at key (see Key.Get or GetAny)
+func Set[T any](ctx context.Context, key *Key[T], v T) context.Context {
+	return context.WithValue(ctx, key, v)
+}
@@ -0,0 +1,33 @@
+package httpctx
+
+import (
+	"context"
+	"slices"
+)
+
+const KPrincipal contextKey = "principal" // for assigning the authenticated caller to context
+
+// Principal is the standard "who is calling", set by auth middleware with SetPrincipal
+type Principal struct {
+	ID     string   // user or service ID
+	Roles  []string // eg: "admin"
+	Method string   // auth scheme, eg: "basic", "bearer", "mtls", "apikey"
+}
+
+// HasRole reports whether p has role (nil p has none)
+func (p *Principal) HasRole(role string) bool {
+	return p != nil && slices.Contains(p.Roles, role)
+}
+
+// SetPrincipal returns ctx with the authenticated caller p (a *Principal, or any app type)
+func SetPrincipal(ctx context.Context, p any) context.Context {
+	return context.WithValue(ctx, KPrincipal, p)
+}
+
+// GetPrincipal returns the caller set with SetPrincipal, if it is a T
+//
+//	p, ok := httpctx.GetPrincipal[*httpctx.Principal](r.Context())
+//	if !ok || !p.HasRole("admin") { ... }
+func GetPrincipal[T any](ctx context.Context) (T, bool) {
+	return GetAny[T](ctx, KPrincipal)
+}
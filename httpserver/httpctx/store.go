@@ -0,0 +1,74 @@
+package httpctx
+
+import (
+	"context"
+	"sync"
+)
+
+const KStore contextKey = "store" // for assigning a request scoped *RequestStore to context
+
+// RequestStore is a mutable per-request key/value store, shared by all middleware of one request.
+// Safe for concurrent use. A nil *RequestStore is empty and drops Set.
+type RequestStore struct {
+	mu sync.Mutex
+	m  map[string]any
+}
+
+// WithStore returns ctx with a new empty RequestStore (httpserver does this for every request)
+func WithStore(ctx context.Context) context.Context {
+	return context.WithValue(ctx, KStore, &RequestStore{})
+}
+
+// Store returns the request store, or nil if none (see WithStore)
+//
+//	httpctx.Store(r.Context()).Set("tenant", tenant) // in auth middleware
+//	tenant, ok := httpctx.StoreGet[*Tenant](r.Context(), "tenant") // in handler
+func Store(ctx context.Context) *RequestStore {
+	s, _ := ctx.Value(KStore).(*RequestStore)
+	return s
+}
+
+// StoreGet returns the stored value for k if it is a T
+func StoreGet[T any](ctx context.Context, k string) (T, bool) {
+	v, ok := Store(ctx).Get(k)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	x, ok := v.(T)
+	return x, ok
+}
+
+// Set k to v (overwrites)
+func (s *RequestStore) Set(k string, v any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.m == nil {
+		s.m = make(map[string]any)
+	}
+	s.m[k] = v
+}
+
+// Get value for k
+func (s *RequestStore) Get(k string) (any, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[k]
+	return v, ok
+}
+
+// Delete k
+func (s *RequestStore) Delete(k string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, k)
+}
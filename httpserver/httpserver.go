@@ -14,7 +14,9 @@ import (
 	"time"
 
 	"github.com/aerth/mostly/httpserver/httpctx"
+	"github.com/aerth/mostly/stackerr"
 	"github.com/aerth/mostly/superchan"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 // HttpServer handles signals, use as main context
@@ -35,6 +37,11 @@ type HttpServer struct {
 	shutdownfunc1   func() // called before http shutdown
 	shutdownfunc    func() // called after http shutdown
 	refreshfunc     func(s *HttpServer) error
+	restartsignals  []os.Signal       // see EnableGracefulRestart
+	autotls         *autocert.Manager // see ListenAndServeAutoTLS
+	accesslog       *accessLog        // see EnableAccessLog
+	listenmu        sync.Mutex
+	listeners       map[string]net.Listener // "http", "https", for Restart
 }
 
 // Config is only for convenience, used by your application and middlewares
@@ -302,6 +309,11 @@ func (s *HttpServer) ListenAndServeAll(httpAddr string, httpsAddr string, cert,
 		s.Server.Handler = s.entrypoint(s.Server.Handler)
 		s.entrypoint = nil // only once, even across refresh
 	}
+	var acme http.Handler
+	if s.autotls != nil {
+		acme = s.autotls.HTTPHandler(nil) // challenges, redirect the rest to https
+	}
+	s.Server.Handler = withRequestCtx(s.Server.Handler, acme, s.accesslog) // httpctx.GetStart etc
 	s.listenAndServe(httpAddr, httpsAddr, cert, key)
 	return context.Cause(s)
 }
@@ -344,6 +356,18 @@ func ServeJsonIndent(w http.ResponseWriter, code int, v any) {
 	enc.Encode(v)
 }
 
+func (s *HttpServer) ServeError(w http.ResponseWriter, err error) {
+	ServeError(w, err)
+}
+
+// ServeError as json {"code":404,"error":"..."}, status from stackerr.HTTPStatus (see stackerr.WithCode)
+//
+// 5xx errors are not shown to the client (see stackerr.ClientMessage), log them yourself or use ServeHTTPError.
+func ServeError(w http.ResponseWriter, err error) {
+	code := stackerr.HTTPStatus(err)
+	ServeJson(w, code, map[string]any{"code": code, "error": stackerr.ClientMessage(err)})
+}
+
 func (s *HttpServer) serveHttps(httpsAddr string, cert, key string, deferfunc func()) {
 	defer deferfunc()
 	if OneClosesBoth {
@@ -353,7 +377,10 @@ func (s *HttpServer) serveHttps(httpsAddr string, cert, key string, deferfunc fu
 	if s.ErrorLog != nil {
 		s.ErrorLog.Printf("https server: starting https://%s", s.Addr)
 	}
-	err := s.Server.ListenAndServeTLS(cert, key)
+	ln, err := s.listen(listenerHTTPS, httpsAddr)
+	if err == nil {
+		err = s.Server.ServeTLS(ln, cert, key)
+	}
 	if s.ErrorLog == nil {
 		log.Printf("wtf: %v", err)
 		return
@@ -374,7 +401,10 @@ func (s *HttpServer) serveHttp(httpAddr string, deferfunc func()) {
 	if s.ErrorLog != nil {
 		s.ErrorLog.Printf("http server: starting http://%s", s.Addr)
 	}
-	err := s.Server.ListenAndServe()
+	ln, err := s.listen(listenerHTTP, httpAddr)
+	if err == nil {
+		err = s.Server.Serve(ln)
+	}
 	if s.ErrorLog == nil {
 		return
 	}
@@ -403,7 +433,8 @@ func (s *HttpServer) listenAndServe(httpAddr string, httpsAddr string, cert, key
 		}
 		wg.Done()
 	})
-	if key != "" && cert != "" && httpsAddr != "" {
+	s.handleRestartSignals()
+	if (key != "" && cert != "" || s.autotls != nil) && httpsAddr != "" {
 		wg.Add(1) // wg: https enabled
 		go s.serveHttps(httpsAddr, cert, key, wg.Done)
 		time.Sleep(time.Second / 2) // race: wait for https to start to reuse for http server
@@ -431,6 +462,11 @@ func (s *HttpServer) Refresh(newmainctx context.Context) error {
 	s.Superchan = superchan.NewMain(newmainctx, s.signalshandled...).(*superchan.Superchan[os.Signal])
 	s.Server = buildserver(s.Superchan, s.Server.Handler)
 	copyHttpServer(s.Server, old)
+	if s.autotls != nil {
+		// ListenAndServeAutoTLS sets both again, ListenAndServeAll must not redirect to https or use the stale manager
+		s.autotls = nil
+		s.Server.TLSConfig = nil
+	}
 	s.basehandler = newbasehandler(s)
 	if s.refreshfunc != nil {
 		if err := s.refreshfunc(s); err != nil {
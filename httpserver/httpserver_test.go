@@ -0,0 +1,265 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aerth/mostly/httpserver/httpctx"
+	"github.com/aerth/mostly/stackerr"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestRequestCtx(t *testing.T) {
+	var start time.Time
+	var elapsed time.Duration
+	var stored int
+	h := withRequestCtx(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+		start = httpctx.GetStart(r.Context())
+		elapsed = httpctx.Elapsed(r.Context())
+		httpctx.Store(r.Context()).Set("k", 1)
+		stored, _ = httpctx.StoreGet[int](r.Context(), "k")
+	}), nil, nil)
+	if withRequestCtx(h, nil, nil) != h {
+		t.Fatal("wrapped twice")
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if start.IsZero() || elapsed < time.Millisecond || stored != 1 {
+		t.Fatalf("start %v elapsed %v stored %d", start, elapsed, stored)
+	}
+}
+
+func TestListenFDNames(t *testing.T) {
+	if got := strings.Join(listenFDNames(3, ""), ","); got != "http,https,fd5" {
+		t.Fatalf("default names: %s", got)
+	}
+	for fdnames, want := range map[string]string{"https:": "https,http", ":http": "https,http", "a::b": "a,http,b", "http:https:": "http,https,fd5"} {
+		if got := strings.Join(listenFDNames(3, fdnames)[:strings.Count(want, ",")+1], ","); got != want {
+			t.Fatalf("%q: got %s, want %s", fdnames, got, want)
+		}
+	}
+	env := withoutListenEnv([]string{"LISTEN_FDS=2", "LISTEN_ADDR=:80", "LISTEN_FDNAMES=http", "HOME=/"})
+	if got := strings.Join(env, " "); got != "LISTEN_ADDR=:80 HOME=/" {
+		t.Fatalf("env: %s", got)
+	}
+}
+
+func TestListenRestart(t *testing.T) {
+	s := New(context.Background(), http.NewServeMux(), syscall.SIGHUP)
+	defer s.Cancel(nil)
+	ln, err := s.listen(listenerHTTP, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	if s.listeners[listenerHTTP] != ln {
+		t.Fatal("listener not recorded for Restart")
+	}
+}
+
+// TestMain runs restartChild instead of the tests in the process started by TestRestart
+func TestMain(m *testing.M) {
+	if os.Getenv("HTTPSERVER_TEST_RESTART_CHILD") != "" {
+		restartChild()
+	}
+	os.Exit(m.Run())
+}
+
+// TestRestart re-executes the test binary (Restart), the child serves one connection on the inherited socket
+func TestRestart(t *testing.T) {
+	t.Setenv("HTTPSERVER_TEST_RESTART_CHILD", "1")
+	s := New(context.Background(), http.NewServeMux(), syscall.SIGHUP)
+	defer s.Cancel(nil)
+	ln, err := s.listen(listenerHTTP, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	ln.Close() // only the child accepts now
+	if cause := context.Cause(s); cause != ErrRestart {
+		t.Fatalf("cause: %v", cause)
+	}
+	conn, err := net.DialTimeout("tcp", ln.Addr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	got, err := io.ReadAll(conn)
+	if err != nil || !strings.HasPrefix(string(got), "child ") || string(got) == fmt.Sprintf("child %d", os.Getpid()) {
+		t.Fatalf("got %q %v", got, err)
+	}
+}
+
+// restartChild answers one connection on the inherited "http" listener with its pid, then exits
+func restartChild() {
+	ln, err := inheritedListener(listenerHTTP)
+	if ln == nil {
+		fmt.Fprintf(os.Stderr, "restart child: no inherited listener: %v\n", err)
+		os.Exit(1)
+	}
+	if os.Getenv("LISTEN_FDS") != "" {
+		fmt.Fprintln(os.Stderr, "restart child: LISTEN_FDS not cleared")
+		os.Exit(1)
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		os.Exit(1)
+	}
+	fmt.Fprintf(conn, "child %d", os.Getpid())
+	conn.Close()
+	os.Exit(0)
+}
+
+func TestAutoTLSRedirect(t *testing.T) {
+	m := &autocert.Manager{Prompt: autocert.AcceptTOS, HostPolicy: autocert.HostWhitelist("example.com")}
+	var served bool
+	h := withRequestCtx(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { served = true }), m.HTTPHandler(nil), nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/x", nil))
+	if served || w.Code != http.StatusFound || w.Header().Get("Location") != "https://example.com/x" {
+		t.Fatalf("plain http not redirected: %d %q", w.Code, w.Header().Get("Location"))
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "https://example.com/x", nil))
+	if !served {
+		t.Fatal("https request not served")
+	}
+	s := New(context.Background(), http.NewServeMux(), syscall.SIGHUP)
+	defer s.Cancel(nil)
+	if err := s.ListenAndServeAutoTLS("", ":0", nil, t.TempDir()); err == nil {
+		t.Fatal("expected error without domains")
+	}
+}
+
+func TestRefreshAutoTLS(t *testing.T) {
+	s := New(context.Background(), http.NewServeMux(), syscall.SIGHUP)
+	m := &autocert.Manager{Prompt: autocert.AcceptTOS, HostPolicy: autocert.HostWhitelist("example.com")}
+	s.autotls, s.Server.TLSConfig = m, m.TLSConfig()
+	s.Server.ReadTimeout = time.Minute
+	s.Cancel(nil)
+	if err := s.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Cancel(nil)
+	if s.autotls != nil || s.Server.TLSConfig != nil {
+		t.Fatal("autotls kept after Refresh")
+	}
+	if s.Server.ReadTimeout != time.Minute {
+		t.Fatal("customization lost")
+	}
+}
+
+func TestAccessLogPanic(t *testing.T) {
+	var buf strings.Builder
+	s := New(context.Background(), http.NewServeMux(), syscall.SIGHUP)
+	defer s.Cancel(nil)
+	s.EnableAccessLog(&buf, LogCommon)
+	h := withRequestCtx(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := http.NewResponseController(w).Hijack(); err == nil {
+			t.Error("recorder hijacked")
+		}
+		panic("boom")
+	}), nil, s.accesslog)
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Fatalf("panic not passed on: %v", p)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/x", nil))
+	}()
+	if !strings.Contains(buf.String(), `"GET /x HTTP/1.1" 500 0`) {
+		t.Fatalf("access log: %q", buf.String())
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	var logs bytes.Buffer
+	HTTPErrorLog = slog.New(slog.NewTextHandler(&logs, nil))
+	defer func() { HTTPErrorLog = nil }()
+	h := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		switch r.URL.Path {
+		case "/panic":
+			panic("db password is hunter2")
+		case "/abort":
+			panic(http.ErrAbortHandler)
+		case "/partial":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte("half"))
+			return errors.New("write failed")
+		}
+		return stackerr.WithCode(errors.New("no such user"), stackerr.CodeNotFound)
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/user", nil))
+	if rec.Code != 404 || rec.Body.String() != `{"code":404,"error":"no such user"}`+"\n" {
+		t.Fatalf("404: %d %s", rec.Code, rec.Body)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil))
+	if rec.Code != 500 || strings.Contains(rec.Body.String(), "hunter2") {
+		t.Fatalf("500 leaked: %s", rec.Body)
+	}
+	if !strings.Contains(logs.String(), "level=ERROR") || !strings.Contains(logs.String(), "hunter2") || !strings.Contains(logs.String(), "path=/panic") {
+		t.Fatalf("logs: %s", logs.String())
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/partial", nil))
+	if rec.Code != http.StatusAccepted || rec.Body.String() != "half" || !strings.Contains(logs.String(), "write failed") {
+		t.Fatalf("header written twice: %d %q", rec.Code, rec.Body)
+	}
+	func() {
+		defer func() {
+			if p := recover(); p != http.ErrAbortHandler {
+				t.Fatalf("ErrAbortHandler not passed on: %v", p)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil))
+	}()
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf strings.Builder
+	s := New(context.Background(), http.NewServeMux(), syscall.SIGHUP)
+	defer s.Cancel(nil)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short and stout"))
+	})
+	for _, tc := range []struct {
+		format LogFormat
+		want   []string
+	}{
+		{LogCommon, []string{`192.0.2.1 - alice [`, `] "GET /tea?x=1 HTTP/1.1" 418 15 0 `}},
+		{LogCombined, []string{`418 15 "http://ref" "pot/1.0" 0 `}},
+		{LogJSON, []string{`"user":"alice"`, `"status":418`, `"bytes":15`, `"path":"/tea?x=1"`, `"user_agent":"pot/1.0"`, `"latency_ms":`}},
+	} {
+		buf.Reset()
+		s.EnableAccessLog(&buf, tc.format)
+		h := withRequestCtx(handler, nil, s.accesslog)
+		r := httptest.NewRequest("GET", "/tea?x=1", nil)
+		r.Header.Set("Referer", "http://ref")
+		r.Header.Set("User-Agent", "pot/1.0")
+		r.SetBasicAuth("alice", "secret")
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		for _, want := range tc.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Fatalf("format %d: missing %q in %q", tc.format, want, buf.String())
+			}
+		}
+	}
+}
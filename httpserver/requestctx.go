@@ -0,0 +1,51 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aerth/mostly/httpserver/httpctx"
+)
+
+// requestctx is the outermost handler, added by ListenAndServeAll, sets per-request context values
+type requestctx struct {
+	next http.Handler
+	acme http.Handler // plain http requests, see ListenAndServeAutoTLS
+	alog *accessLog   // see EnableAccessLog
+}
+
+func (h *requestctx) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), httpctx.KStart, time.Now())
+	ctx = httpctx.WithStore(ctx)
+	r = r.WithContext(ctx)
+	next := h.next
+	if h.acme != nil && r.TLS == nil {
+		next = h.acme
+	}
+	if h.alog == nil {
+		next.ServeHTTP(w, r)
+		return
+	}
+	rec := &responseRecorder{ResponseWriter: w}
+	defer func() {
+		if p := recover(); p != nil {
+			if rec.status == 0 {
+				rec.status = http.StatusInternalServerError // net/http drops the connection
+			}
+			h.alog.log(r, rec)
+			panic(p)
+		}
+		h.alog.log(r, rec)
+	}()
+	next.ServeHTTP(rec, r)
+}
+
+// withRequestCtx wraps handler once (acme and alog may be nil)
+func withRequestCtx(handler http.Handler, acme http.Handler, alog *accessLog) http.Handler {
+	if h, ok := handler.(*requestctx); ok {
+		h.acme, h.alog = acme, alog
+		return h
+	}
+	return &requestctx{next: handler, acme: acme, alog: alog}
+}
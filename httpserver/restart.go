@@ -0,0 +1,222 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aerth/mostly/superchan"
+)
+
+// ErrRestart is the cancel cause (returned by ListenAndServeAll) after Restart started the new process.
+//
+// The new process already has the listening sockets, so main can exit normally after Wait().
+var ErrRestart = errors.New("httpserver: restarting")
+
+// listener names, also used for LISTEN_FDNAMES
+const (
+	listenerHTTP  = "http"
+	listenerHTTPS = "https"
+)
+
+// EnableGracefulRestart calls Restart when one of signals (eg: syscall.SIGUSR2) is caught.
+//
+// Do not also pass these signals to New, they would stop the server without restarting.
+// Persistent across Refresh(), installed by ListenAndServeAll.
+func (s *HttpServer) EnableGracefulRestart(signals ...os.Signal) {
+	s.restartsignals = signals
+}
+
+// Restart re-executes the program with the same arguments and environment, passing the listening
+// sockets (systemd LISTEN_FDS style), then gracefully shuts down this server (cause ErrRestart).
+//
+// The new process's ListenAndServeAll uses the inherited sockets instead of listening, so no
+// connections are refused during a deploy, and in-flight requests finish here (see ShutdownServer).
+func (s *HttpServer) Restart() error {
+	s.listenmu.Lock()
+	var (
+		names []string
+		files []*os.File
+	)
+	for _, name := range []string{listenerHTTP, listenerHTTPS} {
+		ln, ok := s.listeners[name].(interface{ File() (*os.File, error) })
+		if !ok {
+			continue
+		}
+		f, err := ln.File() // dup, not affected by our Close
+		if err != nil {
+			s.listenmu.Unlock()
+			closeFiles(files)
+			return err
+		}
+		names = append(names, name)
+		files = append(files, f)
+	}
+	s.listenmu.Unlock()
+	defer closeFiles(files)
+	if len(files) == 0 {
+		return errors.New("httpserver: restart: no listeners")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files // fd 3, 4, ...
+	cmd.Env = append(withoutListenEnv(os.Environ()),
+		"LISTEN_FDS="+strconv.Itoa(len(files)),
+		"LISTEN_FDNAMES="+strings.Join(names, ":"),
+	)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf("httpserver: restart: started pid %d with %s listeners", cmd.Process.Pid, strings.Join(names, "+"))
+	}
+	cmd.Process.Release()
+	s.Cancel(ErrRestart)
+	return nil
+}
+
+// listen uses an inherited socket if there is one for name, otherwise listens on addr
+func (s *HttpServer) listen(name, addr string) (net.Listener, error) {
+	ln, err := inheritedListener(name)
+	if err != nil && s.ErrorLog != nil {
+		s.ErrorLog.Printf("httpserver: %v", err)
+	}
+	if ln != nil {
+		if s.ErrorLog != nil {
+			s.ErrorLog.Printf("httpserver: using inherited %s listener %s", name, ln.Addr())
+		}
+	} else {
+		var err error
+		if ln, err = net.Listen("tcp", addr); err != nil {
+			return nil, err
+		}
+	}
+	s.listenmu.Lock()
+	defer s.listenmu.Unlock()
+	if s.listeners == nil {
+		s.listeners = map[string]net.Listener{}
+	}
+	s.listeners[name] = ln
+	return ln, nil
+}
+
+// handleRestartSignals until the server context is done
+func (s *HttpServer) handleRestartSignals() {
+	if len(s.restartsignals) == 0 {
+		return
+	}
+	sc := superchan.New(s.Superchan, func(_ context.Context, sig os.Signal) error {
+		if s.ErrorLog != nil {
+			s.ErrorLog.Printf("httpserver: caught %v, restarting", sig)
+		}
+		if err := s.Restart(); err != nil && s.ErrorLog != nil {
+			s.ErrorLog.Printf("httpserver: restart failed: %v", err)
+		}
+		return nil
+	}, false)
+	signal.Notify(sc.Ch(), s.restartsignals...)
+	sc.Defer(func() { signal.Stop(sc.Ch()) })
+}
+
+var (
+	inheritOnce sync.Once
+	inheritmu   sync.Mutex
+	inherited   map[string]net.Listener
+	inheritErr  error // returned once
+)
+
+// inheritedListener returns (only once) the listener passed by a parent process or systemd socket activation,
+// and (only once) errors using the inherited fds
+func inheritedListener(name string) (net.Listener, error) {
+	inheritOnce.Do(loadInherited)
+	inheritmu.Lock()
+	defer inheritmu.Unlock()
+	ln, err := inherited[name], inheritErr
+	delete(inherited, name)
+	inheritErr = nil
+	return ln, err
+}
+
+// loadInherited reads LISTEN_FDS, LISTEN_PID (optional, must be us) and LISTEN_FDNAMES (optional, default "http:https")
+func loadInherited() {
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return
+	}
+	if pid := os.Getenv("LISTEN_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return
+	}
+	inherited = map[string]net.Listener{}
+	var errs []error
+	for i, name := range listenFDNames(n, os.Getenv("LISTEN_FDNAMES")) {
+		f := os.NewFile(uintptr(3+i), name)
+		ln, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("inherited fd %d (%s): %w", 3+i, name, err))
+			continue
+		}
+		inherited[name] = ln
+	}
+	inheritErr = errors.Join(errs...)
+	// don't pass to our children
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDNAMES")
+}
+
+// listenFDNames for n fds, unnamed are "http" then "https" (if not given), then "fdN"
+func listenFDNames(n int, fdnames string) []string {
+	names := make([]string, n)
+	given := strings.Split(fdnames, ":")
+	taken := map[string]bool{}
+	for i := range names {
+		if i < len(given) && given[i] != "" {
+			names[i] = given[i]
+			taken[given[i]] = true
+		}
+	}
+	defaults := []string{listenerHTTP, listenerHTTPS}
+	for i := range names {
+		if names[i] != "" {
+			continue
+		}
+		for len(defaults) > 0 && taken[defaults[0]] {
+			defaults = defaults[1:]
+		}
+		if len(defaults) > 0 {
+			names[i], defaults = defaults[0], defaults[1:]
+		} else {
+			names[i] = "fd" + strconv.Itoa(3+i)
+		}
+	}
+	return names
+}
+
+func withoutListenEnv(env []string) []string {
+	out := env[:0:0]
+	for _, kv := range env {
+		k, _, _ := strings.Cut(kv, "=")
+		if k != "LISTEN_FDS" && k != "LISTEN_PID" && k != "LISTEN_FDNAMES" {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
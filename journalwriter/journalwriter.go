@@ -22,10 +22,13 @@ type JournalWriter struct {
 	Priority // default 0 is 'Emergency' level
 }
 
-// FallbackWriter is used when writing to journal fails
+// FallbackWriter is used when writing to journal fails, eg: superlog.UseConsoleFallback sets a colored console.
 //
 // If nil, write fails will be silent.
-var FallbackWriter = os.Stderr
+//
+// API change: FallbackWriter was an *os.File, it is now any io.Writer.
+// Code using it as a file (eg: FallbackWriter.Fd()) must use os.Stderr instead.
+var FallbackWriter io.Writer = os.Stderr
 
 // DontLogErrors disables printing errors to FallbackWriter
 var DontLogErrors = false
@@ -33,11 +36,22 @@ var DontLogErrors = false
 // DontFallback disables printing failed logs to FallbackWriter (set FallbackWriter nil to disable completely)
 var DontFallback = false
 
+// PrefixPriority makes Write detect the priority from the line (see ParsePriority), using the writer's Priority if none is found.
+//
+// A single log.SetOutput(journalwriter.JournalWriter{journalwriter.PriInfo}) then produces correctly leveled entries.
+var PrefixPriority = false
+
 // Write writes to the journal, falling back to stderr if journal is not available.
 //
-// See DontLogErrors and DontFallback to change behavior when errors occur.
+// See PrefixPriority, DontLogErrors and DontFallback to change behavior when errors occur.
 func (j JournalWriter) Write(b []byte) (int, error) {
-	err := Send(string(b), j.Priority, nil)
+	p := j.Priority
+	if PrefixPriority {
+		if detected, ok := ParsePriority(b); ok {
+			p = detected
+		}
+	}
+	err := sendFunc(string(b), p, nil)
 	if err != nil {
 		if FallbackWriter != nil {
 			if !DontLogErrors {
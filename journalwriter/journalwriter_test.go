@@ -0,0 +1,107 @@
+package journalwriter
+
+import (
+	"log/slog"
+	"testing"
+)
+
+type sent struct {
+	msg    string
+	pri    Priority
+	fields map[string]string
+}
+
+// fakeSend replaces the journal for a test
+func fakeSend(t *testing.T) *[]sent {
+	var got []sent
+	sendFunc = func(msg string, pri Priority, vars map[string]string) error {
+		got = append(got, sent{msg, pri, vars})
+		return nil
+	}
+	t.Cleanup(func() { sendFunc = Send })
+	return &got
+}
+
+func TestSlogHandler(t *testing.T) {
+	got := fakeSend(t)
+	log := slog.New(NewSlogHandler(&SlogOptions{AddSource: true})).With("request-id", 7).WithGroup("db")
+	log.Debug("hidden")
+	log.Warn("slow query", "ms", 120, slog.Group("conn", "host", "x"))
+	if len(*got) != 1 {
+		t.Fatalf("got %d entries", len(*got))
+	}
+	e := (*got)[0]
+	if e.msg != "slow query" || e.pri != PriWarning {
+		t.Fatalf("entry: %+v", e)
+	}
+	for k, v := range map[string]string{"REQUEST_ID": "7", "DB_MS": "120", "DB_CONN_HOST": "x"} {
+		if e.fields[k] != v {
+			t.Fatalf("field %s: %q in %v", k, e.fields[k], e.fields)
+		}
+	}
+	if e.fields["CODE_FUNC"] == "" {
+		t.Fatal("no source")
+	}
+	if LevelPriority(slog.LevelError) != PriErr || LevelPriority(slog.LevelDebug) != PriDebug || FieldName("_a.b") != "A_B" {
+		t.Fatal("mapping")
+	}
+}
+
+func TestPrefixPriority(t *testing.T) {
+	got := fakeSend(t)
+	PrefixPriority = true
+	defer func() { PrefixPriority = false }()
+	w := JournalWriter{PriInfo}
+	for line, want := range map[string]Priority{
+		"[ERROR] disk full":                           PriErr,
+		"2024/01/02 15:04:05 WRN slow":                PriWarning,
+		`time=2024-01-02T15:04:05Z level=DEBUG msg=x`: PriDebug,
+		"FATAL: bye":                                  PriCrit,
+		"an error happened later in the line":         PriInfo, // not a level word
+	} {
+		*got = nil
+		w.Write([]byte(line))
+		if len(*got) != 1 || (*got)[0].pri != want {
+			t.Fatalf("%q: got %+v, want %d", line, *got, want)
+		}
+	}
+}
+
+func TestLeveled(t *testing.T) {
+	got := fakeSend(t)
+	l := NewLeveled()
+	l.Debug.Print("hidden")
+	l.Warn.Print("shown")
+	l.SetMin(PriDebug)
+	l.Debug.Print("now shown")
+	if len(*got) != 2 || (*got)[0].pri != PriWarning || (*got)[1].msg != "now shown\n" {
+		t.Fatalf("got %+v", *got)
+	}
+	l.SetMin(PriErr)
+	if l.Enabled(PriWarning) || !l.Enabled(PriCrit) {
+		t.Fatal("Enabled")
+	}
+}
+
+func TestPriorityVar(t *testing.T) {
+	var none *PriorityVar
+	if !none.Enabled(PriDebug) || new(PriorityVar).Priority() != PriDebug {
+		t.Fatal("zero value should allow everything")
+	}
+	got := fakeSend(t)
+	l := NewLeveled()
+	log := slog.New(NewSlogHandler(&SlogOptions{Level: l.MinVar()}))
+	log.Info("info")
+	l.SetMin(PriNotice)
+	log.Info("hidden")
+	log.Warn("warn")
+	l.Info.Print("hidden")
+	if len(*got) != 2 || (*got)[0].msg != "info" || (*got)[1].msg != "warn" {
+		t.Fatalf("got %+v", *got)
+	}
+	for p := PriCrit; p <= PriDebug; p++ {
+		if p != PriNotice && LevelPriority(PriorityLevel(p)) != p {
+			t.Fatalf("%s: level %s", p, PriorityLevel(p))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package journalwriter
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// PriorityVar is a minimum priority that can be changed at any time, eg: from a signal handler (see superlog.HandleLevelSignal).
+//
+// Like slog.LevelVar, give the same one to several writers to control them together:
+// Leveled, SlogOptions.Level (it is a slog.Leveler) and superlog writers.
+// The zero value (and nil) allows everything (PriDebug).
+type PriorityVar struct {
+	off atomic.Int32 // PriDebug - p, so the zero value is PriDebug
+}
+
+// NewPriorityVar set to p
+func NewPriorityVar(p Priority) *PriorityVar {
+	v := &PriorityVar{}
+	v.Set(p)
+	return v
+}
+
+// Set the minimum priority, less severe entries are dropped (PriDebug to allow everything)
+func (v *PriorityVar) Set(p Priority) {
+	v.off.Store(int32(PriDebug - p))
+}
+
+// Priority is the minimum priority, see Set
+func (v *PriorityVar) Priority() Priority {
+	if v == nil {
+		return PriDebug
+	}
+	return PriDebug - Priority(v.off.Load())
+}
+
+// Enabled if p passes the minimum priority
+func (v *PriorityVar) Enabled(p Priority) bool {
+	return p <= v.Priority()
+}
+
+// Level for slog, see PriorityLevel
+func (v *PriorityVar) Level() slog.Level {
+	return PriorityLevel(v.Priority())
+}
+
+func (v *PriorityVar) String() string {
+	return v.Priority().String()
+}
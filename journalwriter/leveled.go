@@ -0,0 +1,66 @@
+package journalwriter
+
+import (
+	"io"
+	"log"
+)
+
+// Leveled is a bundle of loggers writing to the journal at their priority, see NewLeveled.
+//
+// All share the package fallback settings (FallbackWriter, DontFallback, DontLogErrors) and a minimum priority.
+type Leveled struct {
+	Debug *log.Logger
+	Info  *log.Logger
+	Warn  *log.Logger
+	Error *log.Logger
+
+	min PriorityVar
+}
+
+// NewLeveled loggers (no log flags, the journal timestamps entries), minimum priority is PriInfo (see SetMin)
+func NewLeveled() *Leveled {
+	l := &Leveled{}
+	l.min.Set(PriInfo)
+	l.Debug = log.New(l.Writer(PriDebug), "", 0)
+	l.Info = log.New(l.Writer(PriInfo), "", 0)
+	l.Warn = log.New(l.Writer(PriWarning), "", 0)
+	l.Error = log.New(l.Writer(PriErr), "", 0)
+	return l
+}
+
+// SetMin priority, less severe entries are dropped (PriDebug to log everything). Safe to call at any time.
+func (l *Leveled) SetMin(p Priority) {
+	l.min.Set(p)
+}
+
+// Min priority, see SetMin
+func (l *Leveled) Min() Priority {
+	return l.min.Priority()
+}
+
+// MinVar is the minimum priority, to share with other writers or superlog.HandleLevelSignal
+func (l *Leveled) MinVar() *PriorityVar {
+	return &l.min
+}
+
+// Enabled if p passes the minimum priority
+func (l *Leveled) Enabled(p Priority) bool {
+	return l.min.Enabled(p)
+}
+
+// Writer at priority p, filtered by the minimum priority
+func (l *Leveled) Writer(p Priority) io.Writer {
+	return leveledWriter{l: l, w: JournalWriter{p}}
+}
+
+type leveledWriter struct {
+	l *Leveled
+	w JournalWriter
+}
+
+func (lw leveledWriter) Write(b []byte) (int, error) {
+	if !lw.l.Enabled(lw.w.Priority) {
+		return len(b), nil
+	}
+	return lw.w.Write(b)
+}
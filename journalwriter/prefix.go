@@ -0,0 +1,55 @@
+package journalwriter
+
+import (
+	"bytes"
+	"strings"
+)
+
+// level names and abbreviations used by common loggers (log prefixes, slog, logfmt, zerolog, logrus, ...)
+var priorityNames = map[string]Priority{
+	"emerg": PriEmerg, "emergency": PriEmerg,
+	"alert": PriAlert,
+	"crit":  PriCrit, "critical": PriCrit, "fatal": PriCrit, "ftl": PriCrit, "panic": PriCrit,
+	"err": PriErr, "error": PriErr, "erro": PriErr, "eror": PriErr,
+	"warn": PriWarning, "warning": PriWarning, "wrn": PriWarning,
+	"notice": PriNotice,
+	"info":   PriInfo, "inf": PriInfo,
+	"debug": PriDebug, "dbg": PriDebug, "trace": PriDebug, "trc": PriDebug,
+}
+
+// ParsePriority looks for a level at the start of line (after a log timestamp): "[ERROR]", "ERROR:", "WRN ",
+// or a "level=warn" field within the first few words.
+func ParsePriority(line []byte) (Priority, bool) {
+	fields := bytes.Fields(line)
+	if len(fields) > 5 {
+		fields = fields[:5]
+	}
+	bare := true // only the first word after the timestamp may be a bare level
+	for _, f := range fields {
+		word := strings.ToLower(string(f))
+		if v, ok := strings.CutPrefix(word, "level="); ok {
+			p, ok := priorityNames[strings.Trim(v, `"`)]
+			return p, ok
+		}
+		if strings.HasPrefix(word, "time=") || (word[0] >= '0' && word[0] <= '9') {
+			continue // timestamp
+		}
+		if bare {
+			bare = false
+			if p, ok := priorityNames[strings.Trim(word, `[]():`)]; ok {
+				return p, true
+			}
+		}
+	}
+	return 0, false
+}
+
+var priorityStrings = [...]string{"emerg", "alert", "crit", "error", "warn", "notice", "info", "debug"}
+
+// String is the short level name, eg: "warn"
+func (p Priority) String() string {
+	if p < 0 || int(p) >= len(priorityStrings) {
+		return "unknown"
+	}
+	return priorityStrings[p]
+}
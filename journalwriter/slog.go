@@ -0,0 +1,161 @@
+package journalwriter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+var _ slog.Handler = (*SlogHandler)(nil) // compile-time interface check
+
+// sendFunc is Send, replaced in tests
+var sendFunc = Send
+
+// SlogOptions for NewSlogHandler
+type SlogOptions struct {
+	Level     slog.Leveler // minimum level, default slog.LevelInfo (a *PriorityVar can be shared with other writers)
+	AddSource bool         // adds CODE_FILE, CODE_LINE and CODE_FUNC fields
+}
+
+// SlogHandler is a slog.Handler writing to the journal, attrs become journal fields (uppercase, groups joined with '_').
+//
+// If the journal is not available, entries are written to FallbackWriter as "msg KEY=value ..."
+type SlogHandler struct {
+	opts   SlogOptions
+	fields map[string]string // from WithAttrs
+	prefix string            // from WithGroup
+}
+
+// NewSlogHandler for slog.New(journalwriter.NewSlogHandler(nil)), opts may be nil
+func NewSlogHandler(opts *SlogOptions) *SlogHandler {
+	h := &SlogHandler{fields: map[string]string{}}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// LevelPriority maps a slog level to a journal priority
+func LevelPriority(level slog.Level) Priority {
+	switch {
+	case level >= slog.LevelError+4:
+		return PriCrit
+	case level >= slog.LevelError:
+		return PriErr
+	case level >= slog.LevelWarn:
+		return PriWarning
+	case level >= slog.LevelInfo:
+		return PriInfo
+	}
+	return PriDebug
+}
+
+// PriorityLevel maps a journal priority to the lowest slog level with that priority (PriNotice is between info and warn)
+func PriorityLevel(p Priority) slog.Level {
+	switch {
+	case p <= PriCrit:
+		return slog.LevelError + 4
+	case p == PriErr:
+		return slog.LevelError
+	case p == PriWarning:
+		return slog.LevelWarn
+	case p == PriNotice:
+		return slog.LevelInfo + 2
+	case p == PriInfo:
+		return slog.LevelInfo
+	}
+	return slog.LevelDebug
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	min := slog.LevelInfo
+	if h.opts.Level != nil {
+		min = h.opts.Level.Level()
+	}
+	return level >= min
+}
+
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := maps.Clone(h.fields)
+	r.Attrs(func(a slog.Attr) bool {
+		addField(fields, h.prefix, a)
+		return true
+	})
+	if h.opts.AddSource && r.PC != 0 {
+		fr, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		fields["CODE_FILE"] = fr.File
+		fields["CODE_LINE"] = strconv.Itoa(fr.Line)
+		fields["CODE_FUNC"] = fr.Function
+	}
+	err := sendFunc(r.Message, LevelPriority(r.Level), fields)
+	if err != nil && FallbackWriter != nil {
+		if !DontLogErrors {
+			FallbackWriter.Write([]byte("journalwriter error: " + err.Error() + "\n"))
+		}
+		if !DontFallback {
+			var b strings.Builder
+			b.WriteString(r.Message)
+			for k, v := range fields {
+				fmt.Fprintf(&b, " %s=%q", k, v)
+			}
+			b.WriteByte('\n')
+			FallbackWriter.Write([]byte(b.String()))
+		}
+	}
+	return err
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.fields = maps.Clone(h.fields)
+	for _, a := range attrs {
+		addField(h2.fields, h.prefix, a)
+	}
+	return &h2
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "_"
+	return &h2
+}
+
+// addField flattens groups, and makes the key a valid journal field name
+func addField(fields map[string]string, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "_"
+		}
+		for _, ga := range v.Group() {
+			addField(fields, prefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	fields[FieldName(prefix+a.Key)] = v.String()
+}
+
+// FieldName converts a key to a valid journal field name: uppercase, invalid characters as '_', no leading underscore
+func FieldName(key string) string {
+	b := []byte(strings.ToUpper(key))
+	for i, c := range b {
+		if !(('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '_') {
+			b[i] = '_'
+		}
+	}
+	name := strings.TrimLeft(string(b), "_")
+	if name == "" {
+		return "FIELD"
+	}
+	return name
+}
@@ -0,0 +1,98 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/aerth/mostly/ncode/constraints"
+)
+
+// byte size units, SI (1000) and IEC (1024)
+const (
+	KB uint64 = 1000
+	MB        = KB * 1000
+	GB        = MB * 1000
+	TB        = GB * 1000
+	PB        = TB * 1000
+	EB        = PB * 1000
+
+	KiB uint64 = 1 << 10
+	MiB        = KiB << 10
+	GiB        = MiB << 10
+	TiB        = GiB << 10
+	PiB        = TiB << 10
+	EiB        = PiB << 10
+)
+
+var byteSizeUnits = map[string]uint64{
+	"": 1, "b": 1,
+	"k": KB, "kb": KB, "kib": KiB,
+	"m": MB, "mb": MB, "mib": MiB,
+	"g": GB, "gb": GB, "gib": GiB,
+	"t": TB, "tb": TB, "tib": TiB,
+	"p": PB, "pb": PB, "pib": PiB,
+	"e": EB, "eb": EB, "eib": EiB,
+}
+
+// ParseByteSize string->~uint, eg: "512", "10MiB", "1.5GB", "64k" (units are case insensitive, SI unless "iB")
+//
+// Overflow is an error (eg: "1KiB" for uint8)
+func ParseByteSize[T constraints.Unsigned](in string) (T, error) {
+	s := strings.TrimSpace(in)
+	i := 0
+	for i < len(s) && (s[i] == '.' || ('0' <= s[i] && s[i] <= '9')) {
+		i++
+	}
+	mult, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if i == 0 || !ok {
+		return 0, &strconv.NumError{Func: "ParseByteSize", Num: in, Err: strconv.ErrSyntax}
+	}
+	max := uint64(math.MaxUint64) >> (64 - bitSize[T]())
+	var n uint64
+	if num := s[:i]; strings.Contains(num, ".") {
+		f, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, &strconv.NumError{Func: "ParseByteSize", Num: in, Err: strconv.ErrSyntax}
+		}
+		f *= float64(mult)
+		if f >= float64(max) {
+			return 0, &strconv.NumError{Func: "ParseByteSize", Num: in, Err: strconv.ErrRange}
+		}
+		n = uint64(f)
+	} else {
+		u, err := strconv.ParseUint(num, 10, 64)
+		if err != nil {
+			return 0, &strconv.NumError{Func: "ParseByteSize", Num: in, Err: err.(*strconv.NumError).Err}
+		}
+		hi, lo := bits.Mul64(u, mult)
+		if hi != 0 || lo > max {
+			return 0, &strconv.NumError{Func: "ParseByteSize", Num: in, Err: strconv.ErrRange}
+		}
+		n = lo
+	}
+	return T(n), nil
+}
+
+// FormatByteSize ~uint->string, eg: "10MiB" (iec) or "10.49MB" (si). At most 2 decimals.
+func FormatByteSize[T constraints.Unsigned](n T, iec bool) string {
+	units, names := []uint64{EB, PB, TB, GB, MB, KB}, []string{"EB", "PB", "TB", "GB", "MB", "KB"}
+	if iec {
+		units, names = []uint64{EiB, PiB, TiB, GiB, MiB, KiB}, []string{"EiB", "PiB", "TiB", "GiB", "MiB", "KiB"}
+	}
+	u := uint64(n)
+	for i := range units {
+		if u >= units[i] {
+			s := strconv.FormatFloat(float64(u)/float64(units[i]), 'f', 2, 64)
+			s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+			return s + names[i]
+		}
+	}
+	return strconv.FormatUint(u, 10) + "B"
+}
@@ -0,0 +1,117 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec is a marshal/unmarshal pair, see DefaultCodec
+type Codec struct {
+	Name      string
+	Marshal   func(v any) ([]byte, error)
+	Unmarshal func(b []byte, v any) error
+}
+
+// JsonCodec (encoding/json)
+var JsonCodec = Codec{
+	Name:      "json",
+	Marshal:   json.Marshal,
+	Unmarshal: json.Unmarshal,
+}
+
+// JsonIndentCodec is JsonCodec with the JsonIndent layout
+var JsonIndentCodec = Codec{
+	Name:      "json",
+	Marshal:   func(v any) ([]byte, error) { return json.MarshalIndent(v, "", " ") },
+	Unmarshal: json.Unmarshal,
+}
+
+// CBORCodec (RFC 8949)
+var CBORCodec = Codec{
+	Name:      "cbor",
+	Marshal:   cbor.Marshal,
+	Unmarshal: cbor.Unmarshal,
+}
+
+// MsgPackCodec (msgpack.org)
+var MsgPackCodec = Codec{
+	Name:      "msgpack",
+	Marshal:   msgpack.Marshal,
+	Unmarshal: msgpack.Unmarshal,
+}
+
+// GobCodec (Go to Go only), see EncodeGob
+var GobCodec = Codec{
+	Name:      "gob",
+	Marshal:   func(v any) ([]byte, error) { return EncodeGob(v) },
+	Unmarshal: func(b []byte, v any) error { return gob.NewDecoder(bytes.NewReader(b)).Decode(v) },
+}
+
+// DefaultCodec is used by Encode and Decode (and anydb). Default is JsonCodec.
+//
+// eg: ncode.DefaultCodec = ncode.CBORCodec
+var DefaultCodec = JsonCodec
+
+// Encode using DefaultCodec
+func Encode(v any) ([]byte, error) {
+	return DefaultCodec.Marshal(v)
+}
+
+// Decode using DefaultCodec
+func Decode[T any](b []byte) (T, error) {
+	return DecodeWith[T](DefaultCodec, b)
+}
+
+// DecodeWith codec, same zero-length and Validator semantics as DecodeJson
+func DecodeWith[T any](c Codec, b []byte) (T, error) {
+	var v T
+	if len(b) == 0 {
+		return v, ErrZeroLength
+	}
+	if err := c.Unmarshal(b, &v); err != nil {
+		return v, err
+	}
+	return v, validate(context.Background(), &v)
+}
+
+// EncodeCBOR value
+func EncodeCBOR(v any) ([]byte, error) {
+	return cbor.Marshal(v)
+}
+
+// DecodeCBOR like DecodeJson
+func DecodeCBOR[T any](b []byte) (T, error) {
+	return DecodeWith[T](CBORCodec, b)
+}
+
+// EncodeMsgPack value
+func EncodeMsgPack(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// DecodeMsgPack like DecodeJson
+func DecodeMsgPack[T any](b []byte) (T, error) {
+	return DecodeWith[T](MsgPackCodec, b)
+}
+
+// EncodeGob value. Unlike json, keeps time precision and int/float types (Go to Go only)
+func EncodeGob[T any](v T) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+// DecodeGob like DecodeJson
+func DecodeGob[T any](b []byte) (T, error) {
+	return DecodeWith[T](GobCodec, b)
+}
@@ -0,0 +1,219 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrNotStruct when EncodeCSV/DecodeCSV type is not a struct
+var ErrNotStruct = fmt.Errorf("expected struct type")
+
+// CellError is an error from one csv cell, see DecodeCSV
+type CellError struct {
+	Row    int // 1-based line in the csv (header is row 1)
+	Column string
+	Err    error
+}
+
+func (e *CellError) Error() string {
+	return fmt.Sprintf("row %d column %q: %v", e.Row, e.Column, e.Err)
+}
+
+func (e *CellError) Unwrap() error {
+	return e.Err
+}
+
+type csvField struct {
+	name  string
+	index []int
+}
+
+// exported fields, named by `csv:"name"` tag or field name. `csv:"-"` to skip.
+func csvFields(t reflect.Type) ([]csvField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	var fields []csvField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("csv"); ok {
+			if tag == "-" {
+				continue
+			}
+			if n, _, _ := strings.Cut(tag, ","); n != "" {
+				name = n
+			}
+		}
+		fields = append(fields, csvField{name: name, index: f.Index})
+	}
+	return fields, nil
+}
+
+// EncodeCSV writes a header row and one row per item. T must be a struct.
+//
+// Fields are named by `csv:"name"` tag (or field name), skipped with `csv:"-"`.
+// encoding.TextMarshaler is used if implemented. Nil pointers are empty cells.
+func EncodeCSV[T any](w io.Writer, items []T) error {
+	fields, err := csvFields(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	row := make([]string, len(fields))
+	for i := range fields {
+		row[i] = fields[i].name
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	for i := range items {
+		v := reflect.ValueOf(&items[i]).Elem()
+		for j := range fields {
+			if row[j], err = formatCell(v.FieldByIndex(fields[j].index)); err != nil {
+				return &CellError{Row: i + 2, Column: fields[j].name, Err: err}
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// DecodeCSV reads a header row then one T per row. Does not close reader. T must be a struct.
+//
+// Columns are matched by header name (see EncodeCSV), unknown columns are ignored, empty cells are zero values.
+// Bad cells do not stop decoding: all rows are returned along with every *CellError joined.
+func DecodeCSV[T any](rdr io.Reader) ([]T, error) {
+	fields, err := csvFields(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	cr := csv.NewReader(rdr)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, ErrZeroLength
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]*csvField, len(header)) // column index -> field
+	for i := range header {
+		for j := range fields {
+			if fields[j].name == header[i] {
+				columns[i] = &fields[j]
+				break
+			}
+		}
+	}
+	var (
+		out  []T
+		errs []error
+	)
+	for rownum := 2; ; rownum++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		var item T
+		v := reflect.ValueOf(&item).Elem()
+		for i, cell := range record {
+			if i >= len(columns) || columns[i] == nil || cell == "" {
+				continue
+			}
+			if err := parseCell(v.FieldByIndex(columns[i].index), cell); err != nil {
+				errs = append(errs, &CellError{Row: rownum, Column: columns[i].name, Err: err})
+			}
+		}
+		out = append(out, item)
+	}
+	return out, errors.Join(errs...)
+}
+
+func formatCell(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return "", nil // optional column
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		return formatCell(v.Elem())
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+func parseCell(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return parseCell(v.Elem(), s)
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// extra units for ParseDuration and FormatDuration
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// ParseDuration is time.ParseDuration plus "d" (24h) and "w" (7d) units, eg: "1w", "1d12h", "-1.5d"
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, fmt.Errorf("ncode: invalid duration %q", orig)
+	}
+	var total time.Duration
+	for s != "" {
+		// number
+		i := 0
+		for i < len(s) && (s[i] == '.' || ('0' <= s[i] && s[i] <= '9')) {
+			i++
+		}
+		// unit
+		j := i
+		for j < len(s) && s[j] != '.' && (s[j] < '0' || s[j] > '9') {
+			j++
+		}
+		if i == 0 || j == i {
+			return 0, fmt.Errorf("ncode: invalid duration %q", orig)
+		}
+		var d time.Duration
+		switch unit := s[i:j]; unit {
+		case "d", "w":
+			n, err := strconv.ParseFloat(s[:i], 64)
+			if err != nil {
+				return 0, fmt.Errorf("ncode: invalid duration %q", orig)
+			}
+			d = Day
+			if unit == "w" {
+				d = Week
+			}
+			f := n * float64(d)
+			if f >= math.MaxInt64 { // float64(math.MaxInt64) rounds up to 1<<63
+				return 0, fmt.Errorf("ncode: invalid duration %q", orig)
+			}
+			d = time.Duration(f)
+		default:
+			var err error
+			if d, err = time.ParseDuration(s[:j]); err != nil {
+				return 0, fmt.Errorf("ncode: invalid duration %q", orig)
+			}
+		}
+		if total > math.MaxInt64-d {
+			return 0, fmt.Errorf("ncode: invalid duration %q", orig)
+		}
+		total += d
+		s = s[j:]
+	}
+	if neg {
+		return -total, nil
+	}
+	return total, nil
+}
+
+// FormatDuration like time.Duration.String but with "d" and "w" units, and no zero units, eg: "1d12h", "1w30m0.5s"
+//
+// Output can be parsed by ParseDuration.
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	var sb strings.Builder
+	if d < 0 {
+		sb.WriteByte('-')
+		d = -d
+	}
+	for _, u := range []struct {
+		name string
+		d    time.Duration
+	}{{"w", Week}, {"d", Day}, {"h", time.Hour}, {"m", time.Minute}} {
+		if d >= u.d {
+			fmt.Fprintf(&sb, "%d%s", d/u.d, u.name)
+			d %= u.d
+		}
+	}
+	if d > 0 {
+		sb.WriteString(d.String())
+	}
+	return sb.String()
+}
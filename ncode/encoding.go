@@ -6,7 +6,10 @@
 package ncode
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 
@@ -32,13 +35,16 @@ func toJson(a any) []byte {
 // ErrZeroLength 404 not found
 var ErrZeroLength = fmt.Errorf("cannot decode zero length")
 
+// DecodeJson bytes into T, then Validate (see Validator)
 func DecodeJson[T any](b []byte) (T, error) {
 	var v T
 	if len(b) == 0 {
 		return v, ErrZeroLength
 	}
-	err := json.Unmarshal(b, &v)
-	return v, err
+	if err := json.Unmarshal(b, &v); err != nil {
+		return v, err
+	}
+	return v, validate(context.Background(), &v)
 }
 
 // N2B Number to []byte
@@ -48,7 +54,116 @@ func N2B[E constraints.Unsigned](n E) []byte {
 	return buf
 }
 
-// B2N []byte to number
+// B2N []byte to number (panics if len(b) < 8, see B2NChecked)
 func B2N(b []byte) uint64 {
 	return Binary.Uint64(b)
 }
+
+// ErrShortBuffer when decoding a number from too few bytes
+var ErrShortBuffer = fmt.Errorf("buffer too short")
+
+// B2NChecked []byte to number, without panic
+func B2NChecked(b []byte) (uint64, error) {
+	if len(b) < 8 {
+		return 0, ErrShortBuffer
+	}
+	return Binary.Uint64(b), nil
+}
+
+// I2B signed number to []byte
+func I2B[E constraints.Signed](n E) []byte {
+	return N2B(uint64(n))
+}
+
+// B2I []byte to signed number, without panic
+func B2I(b []byte) (int64, error) {
+	n, err := B2NChecked(b)
+	return int64(n), err
+}
+
+// N2Varint number to []byte (1 to 10 bytes)
+func N2Varint[E constraints.Unsigned](n E) []byte {
+	return binary.AppendUvarint(nil, uint64(n))
+}
+
+// Varint2N []byte to number, see N2Varint
+func Varint2N(b []byte) (uint64, error) {
+	n, l := binary.Uvarint(b)
+	if l <= 0 {
+		return 0, ErrShortBuffer
+	}
+	return n, nil
+}
+
+// I2Varint signed number to []byte (zig-zag, 1 to 10 bytes)
+func I2Varint[E constraints.Signed](n E) []byte {
+	return binary.AppendVarint(nil, int64(n))
+}
+
+// Varint2I []byte to signed number, see I2Varint
+func Varint2I(b []byte) (int64, error) {
+	n, l := binary.Varint(b)
+	if l <= 0 {
+		return 0, ErrShortBuffer
+	}
+	return n, nil
+}
+
+// OrderedN2B number to big endian []byte, so keys sort numerically (bbolt)
+func OrderedN2B[E constraints.Unsigned](n E) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(n))
+}
+
+// OrderedB2N see OrderedN2B
+func OrderedB2N(b []byte) (uint64, error) {
+	if len(b) < 8 {
+		return 0, ErrShortBuffer
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// OrderedI2B signed number to big endian []byte with sign bit flipped, so negative keys sort first (bbolt)
+func OrderedI2B[E constraints.Signed](n E) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(n)^(1<<63))
+}
+
+// OrderedB2I see OrderedI2B
+func OrderedB2I(b []byte) (int64, error) {
+	n, err := OrderedB2N(b)
+	return int64(n ^ (1 << 63)), err
+}
+
+// Byteslike is a string or []byte
+type Byteslike interface {
+	~string | ~[]byte
+}
+
+// B64 encodes with standard base64 (padded)
+func B64[K Byteslike](b K) string {
+	return base64.StdEncoding.EncodeToString([]byte(b))
+}
+
+// UnB64 decodes standard base64 (padded)
+func UnB64[K Byteslike](s K) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(string(s))
+}
+
+// B64URL encodes with url-safe base64 (no padding), for keys and tokens
+func B64URL[K Byteslike](b K) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(b))
+}
+
+// UnB64URL decodes url-safe base64 (no padding)
+func UnB64URL[K Byteslike](s K) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(string(s))
+}
+
+// Hex encodes lowercase hex
+func Hex[K Byteslike](b K) string {
+	return hex.EncodeToString([]byte(b))
+}
+
+// UnHex decodes hex (either case)
+func UnHex[K Byteslike](s K) ([]byte, error) {
+	return hex.DecodeString(string(s))
+}
@@ -0,0 +1,113 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrEnvRequired when a `env:"NAME,required"` variable is not set
+var ErrEnvRequired = fmt.Errorf("required environment variable not set")
+
+// EnvError is an error from one environment variable, see DecodeEnv
+type EnvError struct {
+	Name string
+	Err  error
+}
+
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("env %s: %v", e.Name, e.Err)
+}
+
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeEnv populates a struct from environment variables named prefix + the `env` tag.
+//
+//	type Config struct {
+//		Addr    string        `env:"ADDR" default:":8080"`
+//		Key     string        `env:"KEY,required"`
+//		Timeout time.Duration `env:"TIMEOUT" default:"1d"` // ncode.ParseDuration
+//		Origins []string      `env:"ORIGINS"`              // comma separated
+//	}
+//	cfg, err := ncode.DecodeEnv[Config]("APP_") // APP_ADDR, APP_KEY ...
+//
+// Fields without an `env` tag are ignored, except nested structs which are decoded with the same prefix.
+// Empty variables count as unset. All errors are joined (each an *EnvError).
+func DecodeEnv[T any](prefix string) (T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() != reflect.Struct {
+		return v, ErrNotStruct
+	}
+	return v, errors.Join(decodeEnv(rv, prefix)...)
+}
+
+func decodeEnv(rv reflect.Value, prefix string) []error {
+	var errs []error
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag, ok := f.Tag.Lookup("env")
+		if !ok || tag == "" {
+			if f.Type.Kind() == reflect.Struct {
+				errs = append(errs, decodeEnv(rv.Field(i), prefix)...)
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		name = prefix + name
+		val := os.Getenv(name)
+		if val == "" {
+			val = f.Tag.Get("default")
+		}
+		if val == "" {
+			if opts == "required" {
+				errs = append(errs, &EnvError{Name: name, Err: ErrEnvRequired})
+			}
+			continue
+		}
+		if err := parseEnv(rv.Field(i), val); err != nil {
+			errs = append(errs, &EnvError{Name: name, Err: err})
+		}
+	}
+	return errs
+}
+
+func parseEnv(v reflect.Value, s string) error {
+	switch {
+	case v.Type() == reflect.TypeFor[time.Duration]():
+		d, err := ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8:
+		parts := strings.Split(s, ",")
+		sl := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i := range parts {
+			if err := parseEnv(sl.Index(i), strings.TrimSpace(parts[i])); err != nil {
+				return err
+			}
+		}
+		v.Set(sl)
+		return nil
+	}
+	return parseCell(v, s)
+}
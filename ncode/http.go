@@ -10,5 +10,17 @@ import "net/http"
 // DecodeRequestBody (and close body)
 func DecodeRequestBody[T any](w http.ResponseWriter, r *http.Request) (T, error) {
 	defer r.Body.Close()
-	return DecodeJsonReader[T](r.Body)
+	return decodeJsonReader[T](r.Context(), r.Body, defaultDebugger())
+}
+
+// DecodeRequestBodyLimit (and close body), see DecodeJsonLimit
+func DecodeRequestBodyLimit[T any](w http.ResponseWriter, r *http.Request, max int64) (T, error) {
+	defer r.Body.Close()
+	return decodeJsonLimit[T](r.Context(), r.Body, max)
+}
+
+// DecodeRequestBodyWith debugger (and close body), see DecodeJsonReaderWith
+func DecodeRequestBodyWith[T any](w http.ResponseWriter, r *http.Request, dbg *Debugger) (T, error) {
+	defer r.Body.Close()
+	return decodeJsonReader[T](r.Context(), r.Body, dbg)
 }
@@ -0,0 +1,555 @@
+package ncode
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTwistAnyParallel(t *testing.T) {
+	in := make([]int, 100)
+	for i := range in {
+		in[i] = i
+	}
+	out, err := TwistAnyParallel(context.Background(), in, strconv.Itoa, 4)
+	if err != nil {
+		t.Fatalf("TwistAnyParallel: %v", err)
+	}
+	for i := range out {
+		if out[i] != strconv.Itoa(i) {
+			t.Fatalf("order: got %q at %d", out[i], i)
+		}
+	}
+}
+
+func TestTwistAnyParallelWithError(t *testing.T) {
+	bad := errors.New("bad")
+	_, err := TwistAnyParallelWithError(context.Background(), []string{"1", "x", "3"}, func(s string) (int, error) {
+		if s == "x" {
+			return 0, bad
+		}
+		return strconv.Atoi(s)
+	}, 2)
+	if err != bad {
+		t.Fatalf("expected bad, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = TwistAnyParallel(ctx, []int{1, 2}, strconv.Itoa, 1); err != context.Canceled {
+		t.Fatalf("expected canceled, got %v", err)
+	}
+}
+
+func TestTwistAnyCollect(t *testing.T) {
+	out, err := TwistAnyCollect([]string{"1", "x", "3", "y"}, strconv.Atoi)
+	if len(out) != 2 || out[0] != 1 || out[1] != 3 {
+		t.Fatalf("unexpected output: %v", out)
+	}
+	var ee *ElementError
+	if !errors.As(err, &ee) || ee.Index != 1 {
+		t.Fatalf("expected element 1 error, got %v", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("expected syntax error, got %v", err)
+	}
+}
+
+func TestTwistAnyIndexed(t *testing.T) {
+	out := TwistAnyIndexed([]string{"a", "b", "c"}, func(i int, v string) string { return strconv.Itoa(i) + v })
+	if strings.Join(out, ",") != "0a,1b,2c" {
+		t.Fatalf("unexpected output: %v", out)
+	}
+	if out := TwistAnyIndexed(nil, func(i int, v string) int { return i }); len(out) != 0 {
+		t.Fatalf("nil input: %v", out)
+	}
+	var seen []int
+	got, err := TwistAnyIndexedWithError([]string{"1", "skip", "3"}, func(i int, v string) (int, error) {
+		seen = append(seen, i)
+		if v == "skip" {
+			return 99, ErrSkip
+		}
+		n, err := strconv.Atoi(v)
+		return n*10 + i, err
+	})
+	if err != nil || len(got) != 3 || got[0] != 10 || got[1] != 0 || got[2] != 32 || len(seen) != 3 {
+		t.Fatalf("skip keeps indexes: %v %v %v", got, seen, err)
+	}
+	got, err = TwistAnyIndexedWithError([]string{"1", "x", "3"}, func(i int, v string) (int, error) {
+		return strconv.Atoi(v)
+	})
+	if !errors.Is(err, strconv.ErrSyntax) || got[0] != 1 || got[2] != 0 {
+		t.Fatalf("stops at first error: %v %v", got, err)
+	}
+}
+
+func TestTwistMap(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	upper := TwistMapKeys(m, strings.ToUpper)
+	if len(upper) != 3 || upper["A"] != 1 || upper["C"] != 3 {
+		t.Fatalf("TwistMapKeys: %v", upper)
+	}
+	if collided := TwistMapKeys(m, func(string) string { return "k" }); len(collided) != 1 {
+		t.Fatalf("TwistMapKeys collision: %v", collided)
+	}
+	strs := TwistMapValues(m, strconv.Itoa)
+	if len(strs) != 3 || strs["b"] != "2" {
+		t.Fatalf("TwistMapValues: %v", strs)
+	}
+	keys, values := Keys(m), Values(m)
+	slices.Sort(keys)
+	slices.Sort(values)
+	if strings.Join(keys, ",") != "a,b,c" || !slices.Equal(values, []int{1, 2, 3}) {
+		t.Fatalf("Keys/Values: %v %v", keys, values)
+	}
+	pairs := Pairs(m)
+	if len(pairs) != 3 || !maps.Equal(FromPairs(pairs), m) {
+		t.Fatalf("Pairs round trip: %v", pairs)
+	}
+	if got := FromPairs([]Pair[string, int]{{"a", 1}, {"a", 2}}); got["a"] != 2 {
+		t.Fatalf("FromPairs: later pair should win: %v", got)
+	}
+	if len(Keys[string, int](nil)) != 0 || len(Pairs[string, int](nil)) != 0 || len(FromPairs[string, int](nil)) != 0 {
+		t.Fatal("nil maps")
+	}
+}
+
+func TestTwistChan(t *testing.T) {
+	in := make(chan int, 3)
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+	var got []string
+	for s := range TwistChan(context.Background(), in, strconv.Itoa) {
+		got = append(got, s)
+	}
+	if len(got) != 3 || got[2] != "3" {
+		t.Fatalf("unexpected output: %v", got)
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	type thing struct{ N int }
+	var buf bytes.Buffer
+	if err := EncodeNDJSON(&buf, []thing{{1}, {2}}); err != nil {
+		t.Fatalf("EncodeNDJSON: %v", err)
+	}
+	if buf.String() != "{\"N\":1}\n{\"N\":2}\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+	var got []thing
+	for v, err := range DecodeNDJSON[thing](&buf) {
+		if err != nil {
+			t.Fatalf("DecodeNDJSON: %v", err)
+		}
+		got = append(got, v)
+	}
+	if len(got) != 2 || got[1].N != 2 {
+		t.Fatalf("unexpected decode: %v", got)
+	}
+}
+
+func TestDecodeJsonArrayStream(t *testing.T) {
+	var got []int
+	err := DecodeJsonArrayStream(strings.NewReader(`[1, 2, 3]`), func(v int) error {
+		got = append(got, v)
+		return nil
+	})
+	if err != nil || !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("decode: %v %v", got, err)
+	}
+	if err := DecodeJsonArrayStream(strings.NewReader(`[]`), func(v int) error { t.Fatal("called"); return nil }); err != nil {
+		t.Fatalf("empty: %v", err)
+	}
+	if err := DecodeJsonArrayStream(strings.NewReader(`{"a":1}`), func(v int) error { return nil }); err != ErrNotArray {
+		t.Fatalf("object: expected ErrNotArray, got %v", err)
+	}
+	if err := DecodeJsonArrayStream(strings.NewReader(``), func(v int) error { return nil }); err == nil {
+		t.Fatal("empty input: expected error")
+	}
+	got = nil
+	err = DecodeJsonArrayStream(strings.NewReader(`[1, "two", 3]`), func(v int) error {
+		got = append(got, v)
+		return nil
+	})
+	if err == nil || !slices.Equal(got, []int{1}) {
+		t.Fatalf("wrong element type: %v %v", got, err)
+	}
+	got = nil
+	err = DecodeJsonArrayStream(strings.NewReader(`[1, 2`), func(v int) error {
+		got = append(got, v)
+		return nil
+	})
+	if err == nil || !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("truncated: %v %v", got, err)
+	}
+	stop := errors.New("stop")
+	got = nil
+	rdr := strings.NewReader(`[1, 2, 3, 4]`)
+	err = DecodeJsonArrayStream(rdr, func(v int) error {
+		got = append(got, v)
+		if v == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("early break: %v %v", got, err)
+	}
+}
+
+func TestDecodeJsonLimit(t *testing.T) {
+	if v, err := DecodeJsonLimit[[]int](strings.NewReader("[1,2]"), 5); err != nil || len(v) != 2 {
+		t.Fatalf("DecodeJsonLimit: %v %v", v, err)
+	}
+	var tooLarge *PayloadTooLargeError
+	if _, err := DecodeJsonLimit[[]int](strings.NewReader("[1,2,3]"), 5); !errors.As(err, &tooLarge) {
+		t.Fatalf("expected PayloadTooLargeError, got %v", err)
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	type thing struct {
+		N int
+		S string
+	}
+	for _, c := range []Codec{JsonCodec, JsonIndentCodec, CBORCodec, MsgPackCodec, GobCodec} {
+		b, err := c.Marshal(thing{1, "a"})
+		if err != nil {
+			t.Fatalf("%s: Marshal: %v", c.Name, err)
+		}
+		v, err := DecodeWith[thing](c, b)
+		if err != nil || v.N != 1 || v.S != "a" {
+			t.Fatalf("%s: Decode: %v %v", c.Name, v, err)
+		}
+		if _, err = DecodeWith[thing](c, nil); err != ErrZeroLength {
+			t.Fatalf("%s: expected ErrZeroLength, got %v", c.Name, err)
+		}
+	}
+	for _, c := range []Codec{JsonCodec, JsonIndentCodec} {
+		if b, err := c.Marshal(func() {}); err == nil {
+			t.Fatalf("%s: expected encode error, got %q", c.Name, b)
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	type row struct {
+		Name  string `csv:"name"`
+		Count uint8  `csv:"count"`
+		Skip  bool   `csv:"-"`
+	}
+	var buf bytes.Buffer
+	if err := EncodeCSV(&buf, []row{{"a", 1, true}, {"b,c", 2, true}}); err != nil {
+		t.Fatalf("EncodeCSV: %v", err)
+	}
+	if buf.String() != "name,count\na,1\n\"b,c\",2\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+	rows, err := DecodeCSV[row](strings.NewReader(buf.String() + "d,300\n"))
+	if len(rows) != 3 || rows[1].Name != "b,c" || rows[1].Count != 2 {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+	var cellErr *CellError
+	if !errors.As(err, &cellErr) || cellErr.Row != 4 || cellErr.Column != "count" {
+		t.Fatalf("expected row 4 count error, got %v", err)
+	}
+}
+
+func TestCSVPointers(t *testing.T) {
+	type row struct {
+		At    *time.Time `csv:"at"`
+		Count *int       `csv:"count"`
+	}
+	at, n := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), 7
+	var buf bytes.Buffer
+	if err := EncodeCSV(&buf, []row{{nil, nil}, {&at, &n}}); err != nil {
+		t.Fatalf("EncodeCSV: %v", err)
+	}
+	if buf.String() != "at,count\n,\n2024-01-02T03:04:05Z,7\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+	rows, err := DecodeCSV[row](&buf)
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("DecodeCSV: %v %v", rows, err)
+	}
+	if rows[0].At != nil || rows[0].Count != nil {
+		t.Fatalf("empty cells should stay nil: %+v", rows[0])
+	}
+	if rows[1].At == nil || !rows[1].At.Equal(at) || rows[1].Count == nil || *rows[1].Count != n {
+		t.Fatalf("pointer fields: %+v", rows[1])
+	}
+}
+
+func TestBase64Hex(t *testing.T) {
+	raw := []byte{0xfb, 0xff, 0x00, 'a'} // + and / in std base64
+	if B64(raw) != "+/8AYQ==" || B64("hi") != "aGk=" {
+		t.Fatalf("B64: %s %s", B64(raw), B64("hi"))
+	}
+	if B64URL(raw) != "-_8AYQ" || B64URL("hi") != "aGk" {
+		t.Fatalf("B64URL: %s %s", B64URL(raw), B64URL("hi"))
+	}
+	if Hex(raw) != "fbff0061" || Hex("hi") != "6869" {
+		t.Fatalf("Hex: %s %s", Hex(raw), Hex("hi"))
+	}
+	for name, c := range map[string]struct {
+		enc func([]byte) string
+		dec func(string) ([]byte, error)
+		bad string
+	}{
+		"b64":    {B64[[]byte], UnB64[string], "+/8AYQ"}, // missing padding
+		"b64url": {B64URL[[]byte], UnB64URL[string], "+/8AYQ"},
+		"hex":    {Hex[[]byte], UnHex[string], "fbf"},
+	} {
+		got, err := c.dec(c.enc(raw))
+		if err != nil || !bytes.Equal(got, raw) {
+			t.Fatalf("%s: round trip: %x %v", name, got, err)
+		}
+		if _, err := c.dec(c.bad); err == nil {
+			t.Fatalf("%s: expected error for %q", name, c.bad)
+		}
+	}
+	if got, err := UnB64([]byte("aGk=")); err != nil || string(got) != "hi" {
+		t.Fatalf("UnB64 []byte: %q %v", got, err)
+	}
+	if got, err := UnB64URL([]byte("aGk")); err != nil || string(got) != "hi" {
+		t.Fatalf("UnB64URL []byte: %q %v", got, err)
+	}
+	if got, err := UnHex([]byte("6869")); err != nil || string(got) != "hi" {
+		t.Fatalf("UnHex []byte: %q %v", got, err)
+	}
+	if got, err := UnHex("FBFF0061"); err != nil || !bytes.Equal(got, raw) {
+		t.Fatalf("UnHex upper case: %x %v", got, err)
+	}
+}
+
+func TestVarint(t *testing.T) {
+	for _, n := range []uint64{0, 1, 127, 128, 1 << 32, math.MaxUint64} {
+		b := N2Varint(n)
+		if got, err := Varint2N(b); err != nil || got != n {
+			t.Fatalf("N2Varint(%d): %d %v", n, got, err)
+		}
+		if len(b) > binary.MaxVarintLen64 {
+			t.Fatalf("N2Varint(%d): %d bytes", n, len(b))
+		}
+	}
+	for _, n := range []int64{0, 1, -1, 63, -64, 64, math.MaxInt64, math.MinInt64} {
+		if got, err := Varint2I(I2Varint(n)); err != nil || got != n {
+			t.Fatalf("I2Varint(%d): %d %v", n, got, err)
+		}
+	}
+	if got, err := Varint2I(I2Varint(int8(-128))); err != nil || got != -128 {
+		t.Fatalf("I2Varint(int8): %d %v", got, err)
+	}
+	truncated := N2Varint(uint64(math.MaxUint64))[:3]
+	overlong := bytes.Repeat([]byte{0xff}, 11)
+	for _, b := range [][]byte{nil, truncated, overlong} {
+		if _, err := Varint2N(b); err == nil {
+			t.Fatalf("Varint2N(%x): expected error", b)
+		}
+		if _, err := Varint2I(b); err == nil {
+			t.Fatalf("Varint2I(%x): expected error", b)
+		}
+	}
+}
+
+func TestI2B(t *testing.T) {
+	for _, n := range []int64{0, 1, -1, math.MaxInt64, math.MinInt64} {
+		if got, err := B2I(I2B(n)); err != nil || got != n {
+			t.Fatalf("I2B(%d): %d %v", n, got, err)
+		}
+	}
+	if got, err := B2I(I2B(int32(-5))); err != nil || got != -5 {
+		t.Fatalf("I2B(int32): %d %v", got, err)
+	}
+	if _, err := B2I(I2B(int64(-1))[:7]); err != ErrShortBuffer {
+		t.Fatalf("short: expected ErrShortBuffer, got %v", err)
+	}
+}
+
+func TestOrderedI2B(t *testing.T) {
+	prev := OrderedI2B(int64(-1000))
+	for _, n := range []int64{-1, 0, 1, 1000} {
+		b := OrderedI2B(n)
+		if bytes.Compare(prev, b) >= 0 {
+			t.Fatalf("%d does not sort after previous", n)
+		}
+		if got, err := OrderedB2I(b); err != nil || got != n {
+			t.Fatalf("OrderedB2I: %d %v", got, err)
+		}
+		prev = b
+	}
+	if _, err := B2NChecked([]byte{1}); err != ErrShortBuffer {
+		t.Fatalf("expected ErrShortBuffer, got %v", err)
+	}
+}
+
+func TestParseOverflow(t *testing.T) {
+	if _, err := ParseNumber[uint8]("256"); !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("uint8: expected range error, got %v", err)
+	}
+	if n, err := ParseSigned[int16]("-32768"); err != nil || n != -32768 {
+		t.Fatalf("int16: %d %v", n, err)
+	}
+	if _, err := ParseSigned[int8]("128"); !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("int8: expected range error, got %v", err)
+	}
+	if _, err := ParseFloat[float32]("1e39"); !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("float32: expected range error, got %v", err)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"1d12h":  36 * time.Hour,
+		"1w":     7 * 24 * time.Hour,
+		"-1.5d":  -36 * time.Hour,
+		"90m":    90 * time.Minute,
+		"1h30ms": time.Hour + 30*time.Millisecond,
+		"0":      0,
+	} {
+		got, err := ParseDuration(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseDuration(%q): %v %v", in, got, err)
+		}
+		if again, err := ParseDuration(FormatDuration(got)); err != nil || again != got {
+			t.Fatalf("FormatDuration(%v) = %q does not round trip", got, FormatDuration(got))
+		}
+	}
+	for _, in := range []string{"", "d", "1x", "1.2.3d", "100000w", "200000d", "-200000d", "15251w", "106751d24h", "2562047h1000000h"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Fatalf("ParseDuration(%q): expected error", in)
+		}
+	}
+	if s := FormatDuration(36*time.Hour + 1500*time.Millisecond); s != "1d12h1.5s" {
+		t.Fatalf("FormatDuration: %q", s)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	for in, want := range map[string]uint64{"512": 512, "10MiB": 10 << 20, "1.5GB": 1500000000, "64k": 64000, "2 KiB": 2048} {
+		if got, err := ParseByteSize[uint64](in); err != nil || got != want {
+			t.Fatalf("ParseByteSize(%q): %d %v", in, got, err)
+		}
+	}
+	if _, err := ParseByteSize[uint8]("1KiB"); !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("expected range error, got %v", err)
+	}
+	if _, err := ParseByteSize[uint64]("10XB"); !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("expected syntax error, got %v", err)
+	}
+	if s := FormatByteSize(uint64(10<<20), true); s != "10MiB" {
+		t.Fatalf("FormatByteSize: %q", s)
+	}
+	if s := FormatByteSize(uint32(1500), false); s != "1.5KB" {
+		t.Fatalf("FormatByteSize: %q", s)
+	}
+}
+
+func TestJsonCanonical(t *testing.T) {
+	in := map[string]any{"b": []any{1.0, "<x>\n", nil}, "a": 1e21, "€": true, "c": 1.5e-7, "d": 100}
+	got, err := JsonCanonical(in)
+	if err != nil {
+		t.Fatalf("JsonCanonical: %v", err)
+	}
+	want := `{"a":1e+21,"b":[1,"<x>\n",null],"c":1.5e-7,"d":100,"€":true}`
+	if string(got) != want {
+		t.Fatalf("JsonCanonical:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	got, err := MergePatch([]byte(`{"a":"b","c":{"d":"e","f":"g"},"n":12345678901234567890}`), []byte(`{"a":"z","c":{"f":null}}`))
+	if err != nil {
+		t.Fatalf("MergePatch: %v", err)
+	}
+	if want := `{"a":"z","c":{"d":"e"},"n":12345678901234567890}`; string(got) != want {
+		t.Fatalf("MergePatch: got %s", got)
+	}
+}
+
+func TestJsonPointer(t *testing.T) {
+	doc := []byte(`{"users":[{"name":"a"}],"a/b":1}`)
+	if name, err := DecodeJsonPointer[string](doc, "/users/0/name"); err != nil || name != "a" {
+		t.Fatalf("DecodeJsonPointer: %q %v", name, err)
+	}
+	if n, err := DecodeJsonPointer[int](doc, "/a~1b"); err != nil || n != 1 {
+		t.Fatalf("DecodeJsonPointer: %d %v", n, err)
+	}
+	if _, err := JsonPointerGet(doc, "/users/1"); !errors.Is(err, ErrPointerNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	got, err := JsonPointerSet(doc, "/users/-", map[string]string{"name": "b"})
+	if err != nil {
+		t.Fatalf("JsonPointerSet: %v", err)
+	}
+	if want := `{"a/b":1,"users":[{"name":"a"},{"name":"b"}]}`; string(got) != want {
+		t.Fatalf("JsonPointerSet: got %s", got)
+	}
+}
+
+func TestDecodeEnv(t *testing.T) {
+	type config struct {
+		Addr    string        `env:"ADDR" default:":8080"`
+		Key     string        `env:"KEY,required"`
+		Timeout time.Duration `env:"TIMEOUT" default:"1d"`
+		Origins []string      `env:"ORIGINS"`
+		Port    uint16        `env:"PORT"`
+	}
+	t.Setenv("NCODETEST_ORIGINS", "a, b")
+	t.Setenv("NCODETEST_PORT", "70000")
+	cfg, err := DecodeEnv[config]("NCODETEST_")
+	if cfg.Addr != ":8080" || cfg.Timeout != 24*time.Hour || len(cfg.Origins) != 2 || cfg.Origins[1] != "b" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if !errors.Is(err, ErrEnvRequired) || !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("expected required and range errors, got %v", err)
+	}
+}
+
+func TestDebugger(t *testing.T) {
+	var buf bytes.Buffer
+	dbg := NewDebugger(&buf)
+	dbg.MaxBytes = 12
+	dbg.Redact = func(b []byte) []byte { return bytes.ReplaceAll(b, []byte("hunter2"), []byte("***")) }
+	v, err := DecodeJsonReaderWith[map[string]string](strings.NewReader(`{"password":"hunter2"}`), dbg)
+	if err != nil || v["password"] != "hunter2" {
+		t.Fatalf("DecodeJsonReaderWith: %v %v", v, err)
+	}
+	if out := buf.String(); strings.Contains(out, "hunter2") || !strings.Contains(out, `payload="{\"password\":..."`) {
+		t.Fatalf("unexpected debug output: %s", out)
+	}
+}
+
+type validated struct{ N int }
+
+func (v *validated) Validate() error {
+	if v.N < 0 {
+		return errors.New("negative")
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	if _, err := DecodeJson[validated]([]byte(`{"N":1}`)); err != nil {
+		t.Fatalf("DecodeJson: %v", err)
+	}
+	var verr *ValidationError
+	if _, err := DecodeJson[validated]([]byte(`{"N":-1}`)); !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if _, err := DecodeJsonReader[*validated](strings.NewReader(`{"N":-1}`)); !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if v, err := DecodeJson[*validated]([]byte(`null`)); err != nil || v != nil {
+		t.Fatalf("null: %v %v", v, err)
+	}
+}
@@ -6,38 +6,114 @@
 package ncode
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 )
 
-var DebugJsonRequests = true
+// DebugJsonRequests enables Debug for every DecodeJsonReader call (logging to stderr if Debug is nil).
+//
+// Deprecated: set Debug, or opt in per call with DecodeJsonReaderWith.
+var DebugJsonRequests = false
 
-// DecodeJsonReader does not close reader. set `DebugJsonRequests = true` for debug logging
+// Debug is used by DecodeJsonReader when not nil (nil is off). See DecodeJsonReaderWith for per-call debugging.
+//
+// eg: ncode.Debug = ncode.NewDebugger(os.Stderr)
+var Debug *Debugger
+
+// Debugger logs decoded payloads with their caller, see Debug
+type Debugger struct {
+	Logger   *slog.Logger                // if nil, uses slog.Default()
+	Level    slog.Level                  // default Info
+	MaxBytes int                         // truncate payloads longer than this (0 = no limit)
+	Redact   func(payload []byte) []byte // called before truncation, eg: to hide passwords and tokens
+}
+
+// NewDebugger writing text to w, truncating payloads at 4KiB
+func NewDebugger(w io.Writer) *Debugger {
+	return &Debugger{
+		Logger:   slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		MaxBytes: 4096,
+	}
+}
+
+func (d *Debugger) logger() *slog.Logger {
+	if d.Logger == nil {
+		return slog.Default()
+	}
+	return d.Logger
+}
+
+// Payload logs msg with caller and (redacted, truncated) payload. Nil Debugger is a noop.
+func (d *Debugger) Payload(msg string, payload []byte) {
+	if d == nil {
+		return
+	}
+	if d.Redact != nil {
+		payload = d.Redact(payload)
+	}
+	if d.MaxBytes > 0 && len(payload) > d.MaxBytes {
+		payload = append(payload[:d.MaxBytes:d.MaxBytes], "..."...)
+	}
+	d.logger().Log(context.Background(), d.Level, msg, "caller", callers(2), "payload", string(payload))
+}
+
+// Printf logs a message with caller. Nil Debugger is a noop.
+func (d *Debugger) Printf(format string, args ...any) {
+	if d == nil {
+		return
+	}
+	d.logger().Log(context.Background(), d.Level, fmt.Sprintf(format, args...), "caller", callers(2))
+}
+
+// DecodeJsonReader does not close reader. Set Debug for debug logging
 func DecodeJsonReader[T any](rdr io.Reader) (T, error) {
-	if DebugJsonRequests {
-		return debugDecodeJsonReader[T](rdr)
+	return DecodeJsonReaderWith[T](rdr, defaultDebugger())
+}
+
+// Debug, or stderr if DebugJsonRequests
+func defaultDebugger() *Debugger {
+	if Debug == nil && DebugJsonRequests {
+		return NewDebugger(os.Stderr)
 	}
-	var v T
-	err := json.NewDecoder(rdr).Decode(&v)
-	return v, err
+	return Debug
+}
 
+// DecodeJsonReaderWith debugger (nil is off), does not close reader.
+func DecodeJsonReaderWith[T any](rdr io.Reader, dbg *Debugger) (T, error) {
+	return decodeJsonReader[T](context.Background(), rdr, dbg)
 }
 
-// debug
-func debugDecodeJsonReader[T any](rdr io.Reader) (T, error) {
+// ctx is for ValidateCtx
+func decodeJsonReader[T any](ctx context.Context, rdr io.Reader, dbg *Debugger) (T, error) {
 	var v T
+	if dbg == nil {
+		if err := json.NewDecoder(rdr).Decode(&v); err != nil {
+			return v, err
+		}
+		return v, validate(ctx, &v)
+	}
 	buf, err := io.ReadAll(rdr)
 	if err != nil {
 		return v, err
 	}
+	dbg.Payload("debugjson", buf)
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return v, err
+	}
+	return v, validate(ctx, &v)
+}
+
+// callers up to 6 frames, starting at caller of the caller of callers
+func callers(skip int) string {
 	var caller string
-	for i := 1; i <= 6; i++ {
+	for i := skip + 1; i <= skip+6; i++ {
 		_, file, num, ok := runtime.Caller(i)
 		if !ok {
 			break
@@ -48,8 +124,66 @@ func debugDecodeJsonReader[T any](rdr io.Reader) (T, error) {
 		}
 		caller += fmt.Sprintf("%s:%d ", fname, num)
 	}
-	log.Println("debugjson:", caller)
-	os.Stderr.Write(buf)
-	os.Stderr.Write([]byte{'\n'})
-	return v, json.Unmarshal(buf, &v)
+	return strings.TrimSpace(caller)
+}
+
+// ErrNotArray when DecodeJsonArrayStream input does not start with '['
+var ErrNotArray = fmt.Errorf("expected json array")
+
+// DecodeJsonArrayStream decodes a json array one element at a time, calling fn for each. Does not close reader.
+//
+// Unlike DecodeJsonReader, the whole array is never held in memory. Stops at the first error from fn.
+func DecodeJsonArrayStream[T any](rdr io.Reader, fn func(v T) error) error {
+	dec := json.NewDecoder(rdr)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return ErrNotArray
+	}
+	for dec.More() {
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // closing ']'
+	return err
+}
+
+// PayloadTooLargeError is returned by DecodeJsonLimit when input is longer than Max bytes
+type PayloadTooLargeError struct {
+	Max int64
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("payload too large (max %d bytes)", e.Max)
+}
+
+// DecodeJsonLimit reads at most max bytes, returning *PayloadTooLargeError if there is more. Does not close reader.
+func DecodeJsonLimit[T any](rdr io.Reader, max int64) (T, error) {
+	return decodeJsonLimit[T](context.Background(), rdr, max)
+}
+
+// ctx is for ValidateCtx
+func decodeJsonLimit[T any](ctx context.Context, rdr io.Reader, max int64) (T, error) {
+	var v T
+	buf, err := io.ReadAll(io.LimitReader(rdr, max+1))
+	if err != nil {
+		return v, err
+	}
+	if int64(len(buf)) > max {
+		return v, &PayloadTooLargeError{Max: max}
+	}
+	if len(buf) == 0 {
+		return v, ErrZeroLength
+	}
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return v, err
+	}
+	return v, validate(ctx, &v)
 }
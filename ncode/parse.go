@@ -7,6 +7,7 @@ package ncode
 
 import (
 	"strconv"
+	"unsafe"
 
 	"github.com/aerth/mostly/ncode/constraints"
 )
@@ -16,8 +17,31 @@ func ParseNumber[T constraints.Unsigned](in string) (T, error) {
 	return ParseBase[T](in, 10)
 }
 
-// ParseBase string->~uint
+// ParseBase string->~uint (overflow is an error, eg: "256" for uint8)
 func ParseBase[T constraints.Unsigned](in string, base int) (T, error) {
-	n, err := strconv.ParseUint(in, base, 64)
+	n, err := strconv.ParseUint(in, base, bitSize[T]())
 	return T(n), err
 }
+
+// ParseSigned string->~int (overflow is an error, eg: "128" for int8)
+func ParseSigned[T constraints.Signed](in string) (T, error) {
+	return ParseSignedBase[T](in, 10)
+}
+
+// ParseSignedBase string->~int
+func ParseSignedBase[T constraints.Signed](in string, base int) (T, error) {
+	n, err := strconv.ParseInt(in, base, bitSize[T]())
+	return T(n), err
+}
+
+// ParseFloat string->~float (float32 precision for ~float32)
+func ParseFloat[T constraints.Float](in string) (T, error) {
+	n, err := strconv.ParseFloat(in, bitSize[T]())
+	return T(n), err
+}
+
+// bits of T
+func bitSize[T constraints.Integer | constraints.Float]() int {
+	var v T
+	return int(unsafe.Sizeof(v)) * 8
+}
@@ -0,0 +1,182 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPointerNotFound when a json pointer does not resolve
+var ErrPointerNotFound = fmt.Errorf("json pointer not found")
+
+// MergePatch applies a json merge patch (RFC 7386) to doc, returning the new document.
+//
+// null values in patch remove keys, objects are merged recursively, anything else replaces.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target any
+	if len(bytes.TrimSpace(doc)) != 0 {
+		if err := decodeGeneric(doc, &target); err != nil {
+			return nil, err
+		}
+	}
+	var p any
+	if err := decodeGeneric(patch, &p); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(target, p))
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+// JsonPointerGet returns the raw json at ptr (RFC 6901, eg: "/users/0/name"). Empty ptr is the whole document.
+func JsonPointerGet(doc []byte, ptr string) ([]byte, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := decodeGeneric(doc, &v); err != nil {
+		return nil, err
+	}
+	for _, tok := range tokens {
+		switch x := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = x[tok]; !ok {
+				return nil, fmt.Errorf("%w: %q", ErrPointerNotFound, ptr)
+			}
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, fmt.Errorf("%w: %q", ErrPointerNotFound, ptr)
+			}
+			v = x[i]
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrPointerNotFound, ptr)
+		}
+	}
+	return json.Marshal(v)
+}
+
+// DecodeJsonPointer decodes the value at ptr, see JsonPointerGet
+func DecodeJsonPointer[T any](doc []byte, ptr string) (T, error) {
+	b, err := JsonPointerGet(doc, ptr)
+	if err != nil {
+		var v T
+		return v, err
+	}
+	return DecodeJson[T](b)
+}
+
+// JsonPointerSet sets value at ptr, returning the new document.
+//
+// Parent must exist. Objects get a new or replaced key, arrays accept an existing index, or "-" (or len) to append.
+func JsonPointerSet(doc []byte, ptr string, value any) ([]byte, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var val, v any
+	if err := decodeGeneric(b, &val); err != nil {
+		return nil, err
+	}
+	if err := decodeGeneric(doc, &v); err != nil {
+		return nil, err
+	}
+	v, err = pointerSet(v, tokens, val)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, ptr)
+	}
+	return json.Marshal(v)
+}
+
+func pointerSet(v any, tokens []string, val any) (any, error) {
+	if len(tokens) == 0 {
+		return val, nil
+	}
+	tok := tokens[0]
+	switch x := v.(type) {
+	case map[string]any:
+		if len(tokens) > 1 {
+			if _, ok := x[tok]; !ok {
+				return nil, ErrPointerNotFound
+			}
+		}
+		child, err := pointerSet(x[tok], tokens[1:], val)
+		if err != nil {
+			return nil, err
+		}
+		x[tok] = child
+		return x, nil
+	case []any:
+		i := len(x)
+		if tok != "-" {
+			var err error
+			if i, err = strconv.Atoi(tok); err != nil || i < 0 || i > len(x) {
+				return nil, ErrPointerNotFound
+			}
+		}
+		if i == len(x) {
+			if len(tokens) > 1 {
+				return nil, ErrPointerNotFound
+			}
+			return append(x, val), nil
+		}
+		child, err := pointerSet(x[i], tokens[1:], val)
+		if err != nil {
+			return nil, err
+		}
+		x[i] = child
+		return x, nil
+	}
+	return nil, ErrPointerNotFound
+}
+
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("invalid json pointer %q", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tokens[i], "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// keeps numbers as json.Number
+func decodeGeneric(b []byte, v *any) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
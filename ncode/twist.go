@@ -5,7 +5,10 @@
 
 package ncode
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var ErrSkip = errors.New("skip error")
 
@@ -61,3 +64,66 @@ func TwistAny[T any, K any](a []T, fn func(v T) K) []K {
 	}
 	return k
 }
+
+// ElementError is an error from one element, see TwistAnyCollect
+type ElementError struct {
+	Index int
+	Err   error
+}
+
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("element %d: %v", e.Index, e.Err)
+}
+
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// TwistAnyCollect is like TwistAnyWithError but does not stop at the first error.
+//
+// Returns only the successfully converted elements (in order), and all errors joined (each an *ElementError).
+// skip elements by returning ErrSkip in fn function (not counted as an error)
+func TwistAnyCollect[T any, K any](a []T, fn func(v T) (K, error)) ([]K, error) {
+	var (
+		k    = make([]K, 0, len(a))
+		errs []error
+	)
+	for i := range a {
+		x, err := fn(a[i])
+		if err == ErrSkip {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, &ElementError{Index: i, Err: err})
+			continue
+		}
+		k = append(k, x)
+	}
+	return k, errors.Join(errs...)
+}
+
+// TwistAnyIndexed is TwistAny but fn also receives the element index
+func TwistAnyIndexed[T any, K any](a []T, fn func(i int, v T) K) []K {
+	k := make([]K, len(a))
+	for i := range a {
+		k[i] = fn(i, a[i])
+	}
+	return k
+}
+
+// TwistAnyIndexedWithError is TwistAnyWithError but fn also receives the element index
+// skip elements (but continue indexes) by returning ErrSkip in fn function
+func TwistAnyIndexedWithError[T any, K any](a []T, fn func(i int, v T) (K, error)) ([]K, error) {
+	k := make([]K, len(a))
+	for i := range a {
+		x, err := fn(i, a[i])
+		if err == ErrSkip {
+			continue
+		}
+		if err != nil {
+			return k, err
+		}
+		k[i] = x
+	}
+	return k, nil
+}
@@ -0,0 +1,163 @@
+package ncode
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// counter yields 0, 1, 2... until the consumer stops, recording how far it got
+func counter(produced *int, stopped *bool) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			*produced = i + 1
+			if !yield(i) {
+				*stopped = true
+				return
+			}
+		}
+	}
+}
+
+func TestTwistChanCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan int) // never closed
+	out := TwistChan(ctx, in, strconv.Itoa)
+	in <- 1
+	if v := <-out; v != "1" {
+		t.Fatalf("got %q", v)
+	}
+	cancel()
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Fatal("value after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("out not closed after cancel, goroutine leaked")
+	}
+}
+
+func TestTwistSeqCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var produced int
+	var stopped bool
+	var got []string
+	for s := range TwistSeq(ctx, counter(&produced, &stopped), strconv.Itoa) {
+		got = append(got, s)
+		if len(got) == 3 {
+			cancel()
+		}
+	}
+	if !slices.Equal(got, []string{"0", "1", "2"}) || !stopped || produced != 4 {
+		t.Fatalf("got %v, produced %d, stopped %v", got, produced, stopped)
+	}
+}
+
+func TestTwistSeqBreak(t *testing.T) {
+	var produced int
+	var stopped bool
+	for s := range TwistSeq(context.Background(), counter(&produced, &stopped), strconv.Itoa) {
+		if s == "1" {
+			break
+		}
+	}
+	if !stopped || produced != 2 {
+		t.Fatalf("upstream not stopped by break: produced %d, stopped %v", produced, stopped)
+	}
+}
+
+func TestTwistSeqWithError(t *testing.T) {
+	bad := errors.New("bad")
+	var produced int
+	var stopped bool
+	var got []int
+	var errs []error
+	fn := func(v int) (int, error) {
+		switch v {
+		case 1:
+			return 0, ErrSkip
+		case 2:
+			return 0, bad
+		}
+		return v * 10, nil
+	}
+	for v, err := range TwistSeqWithError(context.Background(), counter(&produced, &stopped), fn) {
+		if err != nil {
+			errs = append(errs, err)
+			continue // caller decides
+		}
+		got = append(got, v)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(got, []int{0, 30}) || len(errs) != 1 || errs[0] != bad || !stopped {
+		t.Fatalf("got %v, errs %v, stopped %v", got, errs, stopped)
+	}
+
+	cause := errors.New("shutting down")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	produced, stopped, errs = 0, false, nil
+	for v, err := range TwistSeqWithError(ctx, counter(&produced, &stopped), func(v int) (int, error) { return v, nil }) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if v == 1 {
+			cancel(cause)
+		}
+	}
+	if len(errs) != 1 || errs[0] != cause || !stopped || produced != 3 {
+		t.Fatalf("cancel: errs %v, produced %d, stopped %v", errs, produced, stopped)
+	}
+}
+
+func TestChanSeq(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan int, 3) // never closed
+	in <- 0
+	in <- 1
+	in <- 2
+	var got []int
+	for v := range ChanSeq(ctx, in) { // blocks forever if cancel is ignored
+		got = append(got, v)
+		if v == 2 {
+			cancel()
+		}
+	}
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("cancel: got %v", got)
+	}
+
+	in = make(chan int, 5)
+	for i := range 5 {
+		in <- i
+	}
+	got = nil
+	for v := range ChanSeq(context.Background(), in) {
+		got = append(got, v)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(got, []int{0, 1}) || len(in) != 3 {
+		t.Fatalf("break: got %v, %d left", got, len(in))
+	}
+	close(in)
+	got = nil
+	for v := range ChanSeq(context.Background(), in) {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{2, 3, 4}) {
+		t.Fatalf("closed: got %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+// Pair is a key and value, see Pairs and FromPairs
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// TwistMapKeys map into a map with different keys, O(n)
+//
+// If fn returns the same key twice, one value wins (map iteration order).
+func TwistMapKeys[K comparable, K2 comparable, V any](m map[K]V, fn func(k K) K2) map[K2]V {
+	out := make(map[K2]V, len(m))
+	for k, v := range m {
+		out[fn(k)] = v
+	}
+	return out
+}
+
+// TwistMapValues map into a map with different values, O(n)
+func TwistMapValues[K comparable, V any, V2 any](m map[K]V, fn func(v V) V2) map[K]V2 {
+	out := make(map[K]V2, len(m))
+	for k, v := range m {
+		out[k] = fn(v)
+	}
+	return out
+}
+
+// Keys of map (unordered)
+func Keys[K comparable, V any](m map[K]V) []K {
+	out := make([]K, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// Values of map (unordered)
+func Values[K comparable, V any](m map[K]V) []V {
+	out := make([]V, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	return out
+}
+
+// Pairs of map (unordered)
+func Pairs[K comparable, V any](m map[K]V) []Pair[K, V] {
+	out := make([]Pair[K, V], 0, len(m))
+	for k, v := range m {
+		out = append(out, Pair[K, V]{Key: k, Value: v})
+	}
+	return out
+}
+
+// FromPairs builds a map, later pairs win
+func FromPairs[K comparable, V any](pairs []Pair[K, V]) map[K]V {
+	out := make(map[K]V, len(pairs))
+	for _, p := range pairs {
+		out[p.Key] = p.Value
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// TwistAnyParallel array into another kind of array, using up to workers goroutines.
+//
+// Output order matches input order. If workers < 1, uses GOMAXPROCS.
+// Returns the context cause if ctx is cancelled before all elements are done (unfinished elements are zero values).
+func TwistAnyParallel[T any, K any](ctx context.Context, a []T, fn func(v T) K, workers int) ([]K, error) {
+	return TwistAnyParallelWithError(ctx, a, func(v T) (K, error) {
+		return fn(v), nil
+	}, workers)
+}
+
+// TwistAnyParallelWithError is TwistAnyWithError using up to workers goroutines.
+//
+// Output order matches input order. If workers < 1, uses GOMAXPROCS.
+// skip elements (but continue indexes) by returning ErrSkip in fn function.
+// The first error (or ctx cancellation) stops all workers and is returned.
+func TwistAnyParallelWithError[T any, K any](ctx context.Context, a []T, fn func(v T) (K, error), workers int) ([]K, error) {
+	k := make([]K, len(a))
+	if len(a) == 0 {
+		return k, context.Cause(ctx)
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(a) {
+		workers = len(a)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var (
+		next atomic.Int64
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(a) {
+					return
+				}
+				x, err := fn(a[i])
+				if err == ErrSkip {
+					continue
+				}
+				if err != nil {
+					cancel(err)
+					return
+				}
+				k[i] = x
+			}
+		}()
+	}
+	wg.Wait()
+	return k, context.Cause(ctx)
+}
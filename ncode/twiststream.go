@@ -0,0 +1,87 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+import (
+	"context"
+	"iter"
+)
+
+// TwistChan things from a channel into another channel, until in is closed or ctx is done.
+//
+// The returned channel has the same buffer size as in, and is closed when done.
+// Works with superchan/cancellable UpdatesChan().
+func TwistChan[T any, K any](ctx context.Context, in <-chan T, fn func(v T) K) <-chan K {
+	out := make(chan K, cap(in))
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- fn(v):
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// TwistSeq lazily transforms a sequence, stopping early if ctx is done.
+func TwistSeq[T any, K any](ctx context.Context, seq iter.Seq[T], fn func(v T) K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for v := range seq {
+			if ctx.Err() != nil || !yield(fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+// TwistSeqWithError lazily transforms a sequence, yielding each error (the caller decides to stop or continue).
+//
+// skip elements by returning ErrSkip in fn function. If ctx is done, the context cause is yielded once, then stops.
+func TwistSeqWithError[T any, K any](ctx context.Context, seq iter.Seq[T], fn func(v T) (K, error)) iter.Seq2[K, error] {
+	return func(yield func(K, error) bool) {
+		for v := range seq {
+			if ctx.Err() != nil {
+				var k K
+				yield(k, context.Cause(ctx))
+				return
+			}
+			x, err := fn(v)
+			if err == ErrSkip {
+				continue
+			}
+			if !yield(x, err) {
+				return
+			}
+		}
+	}
+}
+
+// ChanSeq ranges over a channel until it is closed or ctx is done.
+func ChanSeq[T any](ctx context.Context, in <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok || !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
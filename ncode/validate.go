@@ -0,0 +1,59 @@
+// Copyright © 2023 aerth
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the “Software”), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package ncode
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Validator is checked after decoding (DecodeJson, DecodeJsonReader, Decode, DecodeRequestBody ...)
+type Validator interface {
+	Validate() error
+}
+
+// ValidatorCtx is like Validator, receiving the request context for DecodeRequestBody (otherwise context.Background)
+type ValidatorCtx interface {
+	ValidateCtx(ctx context.Context) error
+}
+
+// ValidationError is returned when a decoded value fails Validate or ValidateCtx
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation: %v", e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validate calls Validate and/or ValidateCtx (value or pointer receiver) on decoded v
+func validate[T any](ctx context.Context, v *T) error {
+	var x any = *v
+	if rv := reflect.ValueOf(x); !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return nil // nothing to validate, eg: "null" into *T
+	}
+	if _, ok := x.(Validator); !ok {
+		if _, ok := x.(ValidatorCtx); !ok {
+			x = v // try pointer receiver
+		}
+	}
+	if val, ok := x.(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return &ValidationError{Err: err}
+		}
+	}
+	if val, ok := x.(ValidatorCtx); ok {
+		if err := val.ValidateCtx(ctx); err != nil {
+			return &ValidationError{Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+)
+
+// Code classifies an error, see WithCode, CodeOf and HTTPStatus
+type Code int
+
+const (
+	CodeUnknown         Code = iota // not classified (500)
+	CodeInvalid                     // bad input (400)
+	CodeUnauthorized                // not logged in (401)
+	CodeForbidden                   // not allowed (403)
+	CodeNotFound                    // (404)
+	CodeConflict                    // already exists, version mismatch (409)
+	CodeTooManyRequests             // rate limited (429)
+	CodeInternal                    // bug or backend failure (500)
+	CodeUnavailable                 // try again later (503)
+	CodeTimeout                     // deadline exceeded (504)
+)
+
+var codeNames = map[Code]string{
+	CodeUnknown:         "unknown",
+	CodeInvalid:         "invalid",
+	CodeUnauthorized:    "unauthorized",
+	CodeForbidden:       "forbidden",
+	CodeNotFound:        "not found",
+	CodeConflict:        "conflict",
+	CodeTooManyRequests: "too many requests",
+	CodeInternal:        "internal",
+	CodeUnavailable:     "unavailable",
+	CodeTimeout:         "timeout",
+}
+
+var codeStatus = map[Code]int{
+	CodeUnknown:         http.StatusInternalServerError,
+	CodeInvalid:         http.StatusBadRequest,
+	CodeUnauthorized:    http.StatusUnauthorized,
+	CodeForbidden:       http.StatusForbidden,
+	CodeNotFound:        http.StatusNotFound,
+	CodeConflict:        http.StatusConflict,
+	CodeTooManyRequests: http.StatusTooManyRequests,
+	CodeInternal:        http.StatusInternalServerError,
+	CodeUnavailable:     http.StatusServiceUnavailable,
+	CodeTimeout:         http.StatusGatewayTimeout,
+}
+
+func (c Code) String() string {
+	if s, ok := codeNames[c]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// HTTPStatus for code (500 if unknown)
+func (c Code) HTTPStatus() int {
+	if s, ok := codeStatus[c]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// WithCode wraps err with a stack trace and code (nil error returns nil)
+func WithCode(err error, code Code) *StackError {
+	if err == nil {
+		return nil
+	}
+	return finish(&StackError{error: err, St: GetFuncCallerInfo(), Code: code})
+}
+
+// CodeOf returns the outermost code in err's chain.
+//
+// Without a code, common std errors are classified (fs.ErrNotExist, fs.ErrPermission, context.DeadlineExceeded)
+func CodeOf(err error) Code {
+	if err == nil {
+		return CodeUnknown
+	}
+	var s *StackError
+	for e := err; errors.As(e, &s); e = s.error {
+		if s.Code != CodeUnknown {
+			return s.Code
+		}
+	}
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return CodeNotFound
+	case errors.Is(err, fs.ErrPermission):
+		return CodeForbidden
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	}
+	return CodeUnknown
+}
+
+// HTTPStatus for err (200 if nil), see CodeOf
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return CodeOf(err).HTTPStatus()
+}
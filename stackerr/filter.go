@@ -0,0 +1,92 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import (
+	"path"
+	"strings"
+)
+
+// FrameFilter returns true to skip a frame (function is the full name, eg: github.com/user/pkg.(*T).Method)
+type FrameFilter func(function, file string) bool
+
+// FrameFilters are applied when a call site is first resolved (the first unfiltered caller is used) and when formatting with %+v (filtered frames are not printed).
+//
+// Example: stackerr.FrameFilters = []stackerr.FrameFilter{stackerr.SkipRuntime, stackerr.SkipPackages("github.com/me/app/internal/log")}
+var FrameFilters []FrameFilter
+
+// SkipRuntime skips runtime frames
+func SkipRuntime(function, file string) bool {
+	pkg := pkgPath(function)
+	return pkg == "runtime" || strings.HasPrefix(pkg, "runtime/")
+}
+
+// SkipStdlib skips standard library frames (including runtime)
+func SkipStdlib(function, file string) bool {
+	pkg := pkgPath(function)
+	if pkg == "main" || pkg == "" {
+		return false
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	return !strings.Contains(first, ".")
+}
+
+// SkipVendor skips frames from vendored packages
+func SkipVendor(function, file string) bool {
+	return strings.Contains(file, "/vendor/")
+}
+
+// SkipPackages skips frames in packages matching any pattern (path.Match, or a prefix ending in "/...")
+func SkipPackages(patterns ...string) FrameFilter {
+	return func(function, file string) bool {
+		pkg := pkgPath(function)
+		for _, p := range patterns {
+			if prefix, ok := strings.CutSuffix(p, "/..."); ok {
+				if pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+					return true
+				}
+				continue
+			}
+			if ok, _ := path.Match(p, pkg); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func skipFrame(function, file string) bool {
+	for _, f := range FrameFilters {
+		if f(function, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// pkgPath from a full function name: github.com/user/pkg.(*T).Method -> github.com/user/pkg
+func pkgPath(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	return function[:slash+1+dot]
+}
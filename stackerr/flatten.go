@@ -0,0 +1,135 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// Frame is one location in a flattened trace, see Flatten
+type Frame struct {
+	Func string // short name, eg: pkg.(*T).Method
+	File string // trimmed path, see Cleanmodulepath
+	Line int
+}
+
+func (f Frame) String() string {
+	return fmt.Sprintf("%s (%s:%d)", f.Func, f.File, f.Line)
+}
+
+// Flatten walks err's whole chain (including Join branches) and returns its frames origin first,
+// each frame once, without frames matched by FrameFilters.
+//
+// Errorf("x: %w", Wrap(err)) is stored outer first, Flatten reorders it so the trace reads top-down from where the error started.
+func Flatten(err error) []Frame {
+	var (
+		frames []Frame
+		seen   = map[Frame]bool{}
+	)
+	for _, st := range chainFrames(err, nil) {
+		if st.skipped() {
+			continue
+		}
+		fr := st.frame()
+		if !seen[fr] {
+			seen[fr] = true
+			frames = append(frames, fr)
+		}
+	}
+	return frames
+}
+
+// chainFrames appends err's frames, innermost first
+func chainFrames(err error, out []FuncCallerInfo) []FuncCallerInfo {
+	switch x := err.(type) {
+	case nil:
+		return out
+	case *StackError:
+		return append(chainFrames(x.error, out), x.St)
+	case *JoinError:
+		for _, branch := range x.errs {
+			out = chainFrames(branch, out)
+		}
+		return append(out, x.St)
+	case interface{ Unwrap() []error }:
+		for _, branch := range x.Unwrap() {
+			out = chainFrames(branch, out)
+		}
+		return out
+	case interface{ Unwrap() error }:
+		return chainFrames(x.Unwrap(), out)
+	}
+	return out
+}
+
+// Frames of the whole captured stack (at most 16), starting at the call site, without frames matched by FrameFilters.
+func (s *StackError) Frames() []Frame {
+	if s == nil || s.St.lazy == nil {
+		return nil
+	}
+	start := s.St.resolve()
+	var frames []Frame
+	found := false
+	for _, fr := range s.St.lazy.callers() {
+		if !found && (fr.Function != start.function || fr.Line != start.line) {
+			continue // before the (filtered) call site
+		}
+		found = true
+		if !skipFrame(fr.Function, fr.File) {
+			frames = append(frames, Frame{Func: filepath.Base(fr.Function), File: Cleanmodulepath(fr.File), Line: fr.Line})
+		}
+	}
+	return frames
+}
+
+// FramesOf returns the call site of every StackError (and Join) in err's chain, outermost first, as stored.
+//
+// See Flatten for a deduplicated, origin first trace.
+func FramesOf(err error) []Frame {
+	sts := chainFrames(err, nil)
+	frames := make([]Frame, 0, len(sts))
+	for i := len(sts) - 1; i >= 0; i-- {
+		frames = append(frames, sts[i].frame())
+	}
+	return frames
+}
+
+func (fci FuncCallerInfo) frame() Frame {
+	fr := fci.resolve()
+	return Frame{Func: fr.funcname, File: Cleanmodulepath(fr.file), Line: fr.line}
+}
+
+// FprintFlat prints err's message followed by its Flatten trace, one "at" line per frame.
+func FprintFlat(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, e := fmt.Fprintln(w, err.Error()); e != nil {
+		return e
+	}
+	for _, fr := range Flatten(err) {
+		if _, e := fmt.Fprintf(w, "\tat %s\n", fr); e != nil {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// goString for %#v: fields, frames and the cause, Go syntax style on one line
+func (s *StackError) goString(f fmt.State) {
+	fmt.Fprintf(f, "&stackerr.StackError{Msg:%q, St:%q", s.Error(), s.St.String())
+	if s.Code != CodeUnknown {
+		fmt.Fprintf(f, ", Code:%d /* %s */", s.Code, s.Code)
+	}
+	if s.Class != ClassNone {
+		fmt.Fprintf(f, ", Class:%d /* %s */", s.Class, s.Class)
+	}
+	if s.Goroutine != 0 {
+		fmt.Fprintf(f, ", Goroutine:%d", s.Goroutine)
+	}
+	fmt.Fprint(f, ", Frames:[]stackerr.Frame{")
+	for i, fr := range s.Frames() {
+		if i > 0 {
+			fmt.Fprint(f, ", ")
+		}
+		fmt.Fprintf(f, "{Func:%q, File:%q, Line:%d}", fr.Func, fr.File, fr.Line)
+	}
+	fmt.Fprint(f, "}")
+	var cause error = s.error
+	if chld := new(StackError); errors.As(s.error, &chld) {
+		cause = chld
+	}
+	fmt.Fprintf(f, ", Cause:%#v}", cause)
+}
+
+// compact for % v: one line for log aggregation, "msg [code=.. class=..] at func (file:line) <- ..." (origin first, see Flatten)
+func compact(err error) string {
+	var b strings.Builder
+	b.WriteString(strings.ReplaceAll(err.Error(), "\n", "; "))
+	var tags []string
+	if code := CodeOf(err); code != CodeUnknown {
+		tags = append(tags, "code="+code.String())
+	}
+	if class := ClassOf(err); class != ClassNone {
+		tags = append(tags, "class="+class.String())
+	}
+	if len(tags) != 0 {
+		fmt.Fprintf(&b, " [%s]", strings.Join(tags, " "))
+	}
+	for i, fr := range Flatten(err) {
+		if i == 0 {
+			b.WriteString(" at ")
+		} else {
+			b.WriteString(" <- ")
+		}
+		b.WriteString(fr.String())
+	}
+	return b.String()
+}
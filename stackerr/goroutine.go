@@ -0,0 +1,65 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// CaptureGoroutine records the goroutine ID in every new StackError (costs a small runtime.Stack call)
+var CaptureGoroutine = false
+
+// MaxFullStack is the buffer size for WrapFatal's runtime.Stack
+var MaxFullStack = 64 << 10
+
+// WrapFatal is Wrap plus the goroutine ID and full runtime.Stack of the current goroutine, for post-mortem analysis (nil error returns nil)
+func WrapFatal(err error) *StackError {
+	return wrapFatal(err, ClassNone)
+}
+
+// wrapFatal, called directly by the exported func
+func wrapFatal(err error, class Class) *StackError {
+	if err == nil {
+		return nil
+	}
+	buf := make([]byte, MaxFullStack)
+	buf = buf[:runtime.Stack(buf, false)]
+	return finish(&StackError{error: err, St: GetFuncCallerInfo(1), Class: class, Goroutine: parseGoroutineID(buf), FullStack: buf})
+}
+
+func goroutineID() int64 {
+	var buf [64]byte
+	return parseGoroutineID(buf[:runtime.Stack(buf[:], false)])
+}
+
+// parseGoroutineID from "goroutine 123 [running]:"
+func parseGoroutineID(stack []byte) int64 {
+	stack, ok := bytes.CutPrefix(stack, []byte("goroutine "))
+	if !ok {
+		return 0
+	}
+	if i := bytes.IndexByte(stack, ' '); i > 0 {
+		stack = stack[:i]
+	}
+	id, _ := strconv.ParseInt(string(stack), 10, 64)
+	return id
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JoinError is an aggregate of errors, see Join
+type JoinError struct {
+	errs []error
+	St   FuncCallerInfo
+}
+
+// Join errors (nil errors are dropped, returns nil if none are left), like errors.Join with a stack trace.
+//
+// errors.Is and errors.As check every branch. %+v prints each branch's stack chain, indented.
+func Join(errs ...error) error {
+	var nonnil []error
+	for _, err := range errs {
+		if err != nil {
+			nonnil = append(nonnil, err)
+		}
+	}
+	if len(nonnil) == 0 {
+		return nil
+	}
+	return &JoinError{errs: nonnil, St: GetFuncCallerInfo(0)}
+}
+
+func (j *JoinError) Error() string {
+	s := make([]string, len(j.errs))
+	for i, err := range j.errs {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// Unwrap for errors.Is and errors.As
+func (j *JoinError) Unwrap() []error {
+	return j.errs
+}
+
+// Errors in the aggregate
+func (j *JoinError) Errors() []error {
+	return j.errs
+}
+
+func (j *JoinError) Format(f fmt.State, c rune) {
+	switch c {
+	case 'v':
+		if f.Flag('#') {
+			fmt.Fprintf(f, "&stackerr.JoinError{St:%q, Errs:[]error{", j.St.String())
+			for i, err := range j.errs {
+				if i > 0 {
+					fmt.Fprint(f, ", ")
+				}
+				fmt.Fprintf(f, "%#v", err)
+			}
+			fmt.Fprint(f, "}}")
+			return
+		}
+		if f.Flag(' ') {
+			fmt.Fprint(f, compact(j))
+			return
+		}
+		if f.Flag('+') {
+			fmt.Fprintf(f, "%d errors", len(j.errs))
+			if !j.St.skipped() {
+				fmt.Fprintf(f, "\n\tfrom %s", j.St.String())
+			}
+			for i, err := range j.errs {
+				branch := fmt.Sprintf("%+v", err)
+				fmt.Fprintf(f, "\n  [%d] %s", i, strings.ReplaceAll(branch, "\n", "\n      "))
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		fmt.Fprint(f, j.Error())
+	case 'q':
+		fmt.Fprintf(f, "%q", j.Error())
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrintOptions for Fprint
+type PrintOptions struct {
+	Color  bool   // ANSI colors, for terminals
+	Indent string // default two spaces
+}
+
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[1;31m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+	ansiDim    = "\033[2m"
+)
+
+// Fprint err for humans: message, then aligned func/file:line columns, with each cause (and Join branch) grouped below.
+//
+// For logs, use %+v instead.
+func Fprint(w io.Writer, err error, opts PrintOptions) error {
+	if err == nil {
+		return nil
+	}
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	p := &printer{w: w, opts: opts}
+	p.print(err, "")
+	return p.err
+}
+
+type printer struct {
+	w    io.Writer
+	opts PrintOptions
+	err  error
+}
+
+func (p *printer) color(c, s string) string {
+	if !p.opts.Color {
+		return s
+	}
+	return c + s + ansiReset
+}
+
+func (p *printer) line(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format+"\n", args...)
+	}
+}
+
+type printLevel struct {
+	msg string
+	st  *FuncCallerInfo
+}
+
+func (p *printer) print(err error, indent string) {
+	var (
+		levels []printLevel
+		join   *JoinError
+	)
+	for e := err; e != nil && join == nil; {
+		switch x := e.(type) {
+		case *StackError:
+			levels = append(levels, printLevel{msg: x.Error(), st: &x.St})
+			e = x.error
+		case *JoinError:
+			if len(levels) == 0 || levels[len(levels)-1].st != nil {
+				levels = append(levels, printLevel{msg: x.Error()})
+			}
+			levels[len(levels)-1].st = &x.St
+			join = x
+		default:
+			if len(levels) == 0 {
+				levels = append(levels, printLevel{msg: x.Error()})
+			}
+			e = errors.Unwrap(e)
+		}
+	}
+	width := 0
+	for _, l := range levels {
+		if l.st != nil && !l.st.skipped() && len(l.st.resolve().funcname) > width {
+			width = len(l.st.resolve().funcname)
+		}
+	}
+	for i, l := range levels {
+		switch {
+		case i == 0 && join != nil && len(levels) == 1:
+			p.line("%s%s", indent, p.color(ansiRed, fmt.Sprintf("%d errors", len(join.errs))))
+		case i == 0:
+			p.line("%s%s", indent, p.color(ansiRed, l.msg))
+		default:
+			p.line("%s%s %s", indent, p.color(ansiYellow, "caused by:"), l.msg)
+		}
+		if l.st != nil && !l.st.skipped() {
+			p.line("%s%s%s  %s", indent, p.opts.Indent, p.color(ansiCyan, fmt.Sprintf("%-*s", width, l.st.resolve().funcname)), p.color(ansiDim, l.st.resolve().filetag))
+		}
+	}
+	if join == nil {
+		return
+	}
+	for i, branch := range join.errs {
+		p.line("%s%s[%d]", indent, p.opts.Indent, i)
+		p.print(branch, indent+strings.Repeat(p.opts.Indent, 2))
+	}
+}
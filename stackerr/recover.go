@@ -0,0 +1,70 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPanic is wrapped by every error from Recover
+var ErrPanic = errors.New("panic")
+
+// Recover converts a recovered panic into a StackError pointing at the panic site (nil returns nil).
+//
+// Call directly in the deferred func:
+//
+//	defer func() {
+//		if err := stackerr.Recover(recover()); err != nil {
+//			log.Printf("%+v", err)
+//		}
+//	}()
+func Recover(recovered any) *StackError {
+	if recovered == nil {
+		return nil
+	}
+	var err error
+	if e, ok := recovered.(error); ok {
+		err = fmt.Errorf("%w: %w", ErrPanic, e)
+	} else {
+		err = fmt.Errorf("%w: %v", ErrPanic, recovered)
+	}
+	return finish(&StackError{error: err, St: panicSite(), Code: CodeInternal})
+}
+
+// Go runs fn in a goroutine, the returned channel receives fn's error (or recovered panic) and is closed.
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		defer func() {
+			if err := Recover(recover()); err != nil {
+				ch <- err
+			}
+		}()
+		ch <- fn()
+	}()
+	return ch
+}
+
+// panicSite is the first non-runtime frame after runtime.gopanic, or Recover's caller (resolved lazily)
+func panicSite() FuncCallerInfo {
+	return capture(2, true) // skip panicSite, Recover
+}
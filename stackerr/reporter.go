@@ -0,0 +1,68 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import "math/rand/v2"
+
+// Reporter receives every StackError created by Wrap, Errorf, WithCode and Recover (see DefaultReporter)
+//
+// Report is called synchronously by the constructor, hand off to a goroutine or channel if slow.
+type Reporter interface {
+	Report(err *StackError)
+}
+
+// ReporterFunc adapts a func to Reporter
+type ReporterFunc func(err *StackError)
+
+func (f ReporterFunc) Report(err *StackError) {
+	f(err)
+}
+
+// DefaultReporter (nil by default) for integrations (sentry, an error bucket in anydb, ...)
+var DefaultReporter Reporter
+
+// ReportFilter (optional) returns false to not report an error
+var ReportFilter func(err *StackError) bool
+
+// ReportSampleRate between 0 and 1, errors passing ReportFilter are reported at this rate
+var ReportSampleRate = 1.0
+
+// finish a new StackError: CaptureGoroutine, then report
+func finish(s *StackError) *StackError {
+	if CaptureGoroutine && s.Goroutine == 0 {
+		s.Goroutine = goroutineID()
+	}
+	return report(s)
+}
+
+func report(s *StackError) *StackError {
+	r := DefaultReporter
+	if r == nil {
+		return s
+	}
+	if ReportFilter != nil && !ReportFilter(s) {
+		return s
+	}
+	if ReportSampleRate < 1 && rand.Float64() >= ReportSampleRate {
+		return s
+	}
+	r.Report(s)
+	return s
+}
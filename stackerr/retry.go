@@ -0,0 +1,116 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+import "errors"
+
+// Class for retry decisions, see MarkRetryable, MarkTemporary and MarkFatal
+type Class int
+
+const (
+	ClassNone      Class = iota
+	ClassRetryable       // try again (maybe with backoff)
+	ClassTemporary       // transient condition, will pass on its own
+	ClassFatal           // never retry
+)
+
+func (c Class) String() string {
+	switch c {
+	case ClassRetryable:
+		return "retryable"
+	case ClassTemporary:
+		return "temporary"
+	case ClassFatal:
+		return "fatal"
+	}
+	return "none"
+}
+
+// MarkRetryable wraps err with a stack trace (nil error returns nil)
+func MarkRetryable(err error) *StackError {
+	if err == nil {
+		return nil
+	}
+	return finish(&StackError{error: err, St: GetFuncCallerInfo(), Class: ClassRetryable})
+}
+
+// MarkTemporary wraps err with a stack trace (nil error returns nil)
+func MarkTemporary(err error) *StackError {
+	if err == nil {
+		return nil
+	}
+	return finish(&StackError{error: err, St: GetFuncCallerInfo(), Class: ClassTemporary})
+}
+
+// MarkFatal is WrapFatal (full stack) marked ClassFatal (nil error returns nil)
+func MarkFatal(err error) *StackError {
+	return wrapFatal(err, ClassFatal)
+}
+
+// ClassOf returns the outermost class in err's chain
+func ClassOf(err error) Class {
+	var s *StackError
+	for e := err; errors.As(e, &s); e = s.error {
+		if s.Class != ClassNone {
+			return s.Class
+		}
+	}
+	return ClassNone
+}
+
+// IsFatal if err is marked fatal
+func IsFatal(err error) bool {
+	return ClassOf(err) == ClassFatal
+}
+
+// IsTemporary if err is marked temporary, or has a Temporary() or Timeout() method returning true (eg: net.Error)
+func IsTemporary(err error) bool {
+	switch ClassOf(err) {
+	case ClassTemporary:
+		return true
+	case ClassNone:
+		var tmp interface{ Temporary() bool }
+		if errors.As(err, &tmp) && tmp.Temporary() {
+			return true
+		}
+		var timeout interface{ Timeout() bool }
+		return errors.As(err, &timeout) && timeout.Timeout()
+	}
+	return false
+}
+
+// IsRetryable if err is marked retryable or temporary.
+// Without a mark: IsTemporary, or coded CodeUnavailable, CodeTimeout or CodeTooManyRequests.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch ClassOf(err) {
+	case ClassRetryable, ClassTemporary:
+		return true
+	case ClassFatal:
+		return false
+	}
+	switch CodeOf(err) {
+	case CodeUnavailable, CodeTimeout, CodeTooManyRequests:
+		return true
+	}
+	return IsTemporary(err)
+}
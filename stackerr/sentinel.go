@@ -0,0 +1,58 @@
+// Copyright (c) 2024 aerth
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package stackerr
+
+// Sentinel is a cheap package-level error without a stack, see New and Here
+type Sentinel struct {
+	msg string
+}
+
+// New sentinel error, attach the call site when returning it:
+//
+//	var ErrNoUser = stackerr.New("no such user")
+//
+//	func find() error { return stackerr.Here(ErrNoUser) } // errors.Is(err, ErrNoUser) is true
+func New(msg string) *Sentinel {
+	return &Sentinel{msg: msg}
+}
+
+func (e *Sentinel) Error() string {
+	return e.msg
+}
+
+// Here is stackerr.Here(e)
+func (e *Sentinel) Here() *StackError {
+	return Here(e, 1)
+}
+
+// Here attaches the caller's stack to err, unless err already is a *StackError (nil error returns nil)
+func Here(err error, skips ...int) *StackError {
+	if err == nil {
+		return nil
+	}
+	if s, ok := err.(*StackError); ok {
+		return s
+	}
+	skip := 0
+	if len(skips) > 0 {
+		skip = skips[0]
+	}
+	return finish(&StackError{error: err, St: GetFuncCallerInfo(skip)})
+}
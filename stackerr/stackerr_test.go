@@ -0,0 +1,297 @@
+package stackerr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	inner := Wrap(errors.New("boom"), -1)
+	err := Errorf("outer: %w", inner)
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Error("failed", "err", err)
+	out := buf.String()
+	for _, want := range []string{`err.msg="outer: boom"`, "err.func=stackerr.TestLogValue", "err.file=", `err.cause.msg=boom`, "err.cause.func="} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in %s", want, out)
+		}
+	}
+}
+
+func TestLogValueCause(t *testing.T) {
+	err := Errorf("outer: %w", Wrap(errors.New("boom")))
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Error("failed", "err", err)
+	if out := buf.String(); !strings.Contains(out, "err.cause.func=stackerr.TestLogValueCause") {
+		t.Fatalf("cause frame: %s", out)
+	}
+}
+
+func TestCode(t *testing.T) {
+	err := Errorf("lookup: %w", WithCode(errors.New("no such user"), CodeNotFound))
+	if CodeOf(err) != CodeNotFound || HTTPStatus(err) != 404 {
+		t.Fatalf("got %v %d", CodeOf(err), HTTPStatus(err))
+	}
+	if outer := WithCode(err, CodeInvalid); CodeOf(outer) != CodeInvalid {
+		t.Fatalf("outermost code should win: %v", CodeOf(outer))
+	}
+	if got := CodeOf(fmt.Errorf("open: %w", fs.ErrNotExist)); got != CodeNotFound {
+		t.Fatalf("fs.ErrNotExist: %v", got)
+	}
+	if HTTPStatus(errors.New("x")) != 500 || HTTPStatus(nil) != 200 || WithCode(nil, CodeInternal) != nil {
+		t.Fatal("defaults")
+	}
+}
+
+func TestJoin(t *testing.T) {
+	if Join(nil, nil) != nil {
+		t.Fatal("expected nil")
+	}
+	err := Join(Errorf("a: %w", fs.ErrNotExist), nil, WithCode(errors.New("b"), CodeInvalid))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatal("errors.Is across branches")
+	}
+	var s *StackError
+	if !errors.As(err, &s) || err.Error() != "a: file does not exist\nb" {
+		t.Fatalf("errors.As / Error: %q", err.Error())
+	}
+	out := fmt.Sprintf("%+v", err)
+	if !strings.Contains(out, "2 errors") || !strings.Contains(out, "[1] b\n") || strings.Count(out, "stackerr.TestJoin") != 3 {
+		t.Fatalf("format:\n%s", out)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	err := <-Go(func() error {
+		var m map[string]int
+		m["x"] = 1 // panic site
+		return nil
+	})
+	var s *StackError
+	if !errors.As(err, &s) || !errors.Is(err, ErrPanic) || CodeOf(err) != CodeInternal {
+		t.Fatalf("got %v", err)
+	}
+	if !strings.Contains(s.St.resolve().funcname, "TestRecover.func1") {
+		t.Fatalf("wrong panic site: %s", s.St)
+	}
+	if err := <-Go(func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if Recover(nil) != nil {
+		t.Fatal("expected nil")
+	}
+}
+
+func wrapHelper(err error) *StackError {
+	return Wrap(err) // captures wrapHelper
+}
+
+func TestFrameFilters(t *testing.T) {
+	defer func() { FrameFilters = nil }()
+	if got := wrapHelper(errors.New("x")).St.resolve().funcname; got != "stackerr.wrapHelper" {
+		t.Fatalf("unfiltered: %s", got)
+	}
+	FrameFilters = []FrameFilter{func(function, file string) bool { return strings.HasSuffix(function, ".wrapHelper") }}
+	err := wrapHelper(errors.New("x"))
+	if got := err.St.resolve().funcname; got != "stackerr.TestFrameFilters" {
+		t.Fatalf("filtered capture: %s", got)
+	}
+	FrameFilters = []FrameFilter{SkipPackages("github.com/aerth/mostly/...")}
+	if out := fmt.Sprintf("%+v", err); strings.Contains(out, "from") {
+		t.Fatalf("filtered format: %s", out)
+	}
+	for fn, want := range map[string]bool{"net/http.(*conn).serve": true, "main.main": false, "github.com/a/b.F": false} {
+		if SkipStdlib(fn, "") != want {
+			t.Fatalf("SkipStdlib(%s)", fn)
+		}
+	}
+	if !SkipRuntime("runtime.gopanic", "") || SkipRuntime("github.com/a/runtime.F", "") {
+		t.Fatal("SkipRuntime")
+	}
+}
+
+func TestFprint(t *testing.T) {
+	err := Errorf("save: %w", Join(Wrap(errors.New("disk full")), errors.New("plain")))
+	var buf bytes.Buffer
+	if err := Fprint(&buf, err, PrintOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"save: disk full\nplain\n  stackerr.TestFprint  stackerr", "caused by: disk full\nplain", "  [0]\n    disk full\n      stackerr.TestFprint", "  [1]\n    plain\n"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	buf.Reset()
+	Fprint(&buf, err, PrintOptions{Color: true})
+	if !strings.Contains(buf.String(), ansiRed) {
+		t.Fatal("no color")
+	}
+}
+
+func TestReporter(t *testing.T) {
+	var got []*StackError
+	DefaultReporter = ReporterFunc(func(err *StackError) { got = append(got, err) })
+	ReportFilter = func(err *StackError) bool { return CodeOf(err) != CodeNotFound }
+	defer func() { DefaultReporter, ReportFilter = nil, nil }()
+	Wrap(errors.New("a"))
+	Errorf("b")
+	WithCode(errors.New("c"), CodeNotFound) // filtered
+	if len(got) != 2 || got[1].Error() != "b" || got[0].St.resolve().funcname != "stackerr.TestReporter" {
+		t.Fatalf("got %v", got)
+	}
+	ReportSampleRate = 0
+	defer func() { ReportSampleRate = 1 }()
+	Errorf("d")
+	if len(got) != 2 {
+		t.Fatal("sampled out error was reported")
+	}
+}
+
+func TestGoroutine(t *testing.T) {
+	if err := Wrap(errors.New("x")); err.Goroutine != 0 {
+		t.Fatal("captured without CaptureGoroutine")
+	}
+	CaptureGoroutine = true
+	defer func() { CaptureGoroutine = false }()
+	err := Wrap(errors.New("x"))
+	if err.Goroutine == 0 || !strings.Contains(fmt.Sprintf("%+v", err), fmt.Sprintf("[goroutine %d]", err.Goroutine)) {
+		t.Fatalf("goroutine: %+v", err)
+	}
+	fatal := WrapFatal(errors.New("y"))
+	if fatal.Goroutine != err.Goroutine || !bytes.Contains(fatal.FullStack, []byte("TestGoroutine")) {
+		t.Fatalf("fatal: %+v", fatal)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	inner := Wrap(errors.New("x"))
+	err := Errorf("a: %w", Join(inner, fmt.Errorf("b: %w", Errorf("c"))))
+	frames := Flatten(err)
+	if len(frames) != 2 { // same line frames are deduplicated
+		t.Fatalf("expected 2 unique frames, got %v", frames)
+	}
+	if frames[0].Line != inner.St.resolve().line || frames[0].Func != "stackerr.TestFlatten" || frames[1].Line != frames[0].Line+1 {
+		t.Fatalf("not origin first: %v", frames)
+	}
+	var buf bytes.Buffer
+	FprintFlat(&buf, err)
+	if strings.Count(buf.String(), "\tat stackerr.TestFlatten (") != 2 {
+		t.Fatalf("FprintFlat:\n%s", buf.String())
+	}
+}
+
+var errTestSentinel = New("sentinel")
+
+func TestSentinel(t *testing.T) {
+	err := Here(errTestSentinel)
+	if !errors.Is(err, errTestSentinel) || err.St.resolve().funcname != "stackerr.TestSentinel" {
+		t.Fatalf("Here: %+v", err)
+	}
+	if Here(err) != err {
+		t.Fatal("Here should not restack a StackError")
+	}
+	if m := errTestSentinel.Here(); m.St.resolve().funcname != "stackerr.TestSentinel" || m.Error() != "sentinel" {
+		t.Fatalf("method Here: %+v", m)
+	}
+}
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string { return "i/o timeout" }
+func (timeoutErr) Timeout() bool { return true }
+
+func TestRetryable(t *testing.T) {
+	base := errors.New("x")
+	if IsRetryable(base) || IsRetryable(nil) || !IsRetryable(MarkRetryable(base)) {
+		t.Fatal("MarkRetryable")
+	}
+	if !IsTemporary(fmt.Errorf("read: %w", timeoutErr{})) || !IsRetryable(timeoutErr{}) {
+		t.Fatal("Timeout() method")
+	}
+	if fatal := MarkFatal(base); fatal.St.resolve().funcname != "stackerr.TestRetryable" || len(fatal.FullStack) == 0 {
+		t.Fatalf("MarkFatal: %+v", fatal)
+	}
+	if IsRetryable(MarkFatal(MarkRetryable(base))) || !IsFatal(Errorf("w: %w", MarkFatal(base))) {
+		t.Fatal("outermost fatal wins")
+	}
+	if !IsRetryable(WithCode(base, CodeUnavailable)) || !IsRetryable(Errorf("w: %w", MarkTemporary(base))) {
+		t.Fatal("code / temporary")
+	}
+}
+
+func TestFrames(t *testing.T) {
+	inner := Wrap(errors.New("x"))
+	err := Errorf("a: %w", inner)
+	frames := err.Frames()
+	if len(frames) < 2 || frames[0].Func != "stackerr.TestFrames" || frames[0].Line != inner.St.resolve().line+1 || frames[1].Func != "testing.tRunner" {
+		t.Fatalf("Frames: %v", frames)
+	}
+	of := FramesOf(err)
+	if len(of) != 2 || of[0].Line != frames[0].Line || of[1].Line != inner.St.resolve().line || !strings.HasSuffix(of[0].File, "stackerr_test.go") {
+		t.Fatalf("FramesOf: %v", of)
+	}
+	if (*StackError)(nil).Frames() != nil || len(FramesOf(errors.New("plain"))) != 0 {
+		t.Fatal("empty cases")
+	}
+}
+
+func TestSetPathTrimming(t *testing.T) {
+	defer SetPathTrimming()
+	SetPathTrimming("/src", "/src/app")
+	if got := Cleanmodulepath("/src/app/pkg/x.go"); got != "pkg/x.go" {
+		t.Fatalf("longest prefix: %s", got)
+	}
+	if got := Cleanmodulepath("/other/x.go"); got != "/other/x.go" {
+		t.Fatalf("no match: %s", got)
+	}
+	SetPathTrimming()
+	if got := Cleanmodulepath(mainpwd + "/x.go"); got != "x.go" {
+		t.Fatalf("default: %s", got)
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	err := Errorf("outer: %w", WithCode(errors.New("inner\nline"), CodeInvalid))
+	line := fmt.Sprintf("% v", err)
+	if strings.Contains(line, "\n") || !strings.HasPrefix(line, "outer: inner; line [code=invalid] at stackerr.TestFormatVerbs (") {
+		t.Fatalf("compact: %s", line)
+	}
+	if got := fmt.Sprintf("% v", Join(err, errors.New("b"))); strings.Contains(got, "\n") {
+		t.Fatalf("compact join: %s", got)
+	}
+	gs := fmt.Sprintf("%#v", err)
+	for _, want := range []string{`&stackerr.StackError{Msg:"outer: inner\nline"`, `Frames:[]stackerr.Frame{{Func:"stackerr.TestFormatVerbs"`, `Cause:&stackerr.StackError{Msg:"inner\nline"`, "Code:1 /* invalid */", `Cause:&errors.errorString{`} {
+		if !strings.Contains(gs, want) {
+			t.Fatalf("missing %q in %s", want, gs)
+		}
+	}
+	if fmt.Sprintf("%v", err) != err.Error() {
+		t.Fatal("plain verb changed")
+	}
+}
+
+var benchErr = errors.New("boom")
+
+// BenchmarkWrap is the hot path: capture only, the error is usually handled without formatting
+func BenchmarkWrap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if Wrap(benchErr) == nil {
+			b.Fatal("nil")
+		}
+	}
+}
+
+// BenchmarkWrapFormat captures and formats the call site once
+func BenchmarkWrapFormat(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%+v", Wrap(benchErr))
+	}
+}
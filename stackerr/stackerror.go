@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"strings"
 )
 
@@ -32,7 +33,7 @@ func Wrap(err error, skips ...int) *StackError {
 		return nil
 	}
 	st := GetFuncCallerInfo(skips...)
-	return &StackError{error: err, St: st}
+	return finish(&StackError{error: err, St: st})
 }
 
 func containsErr(args []interface{}) bool {
@@ -56,22 +57,71 @@ func Errorf(format string, args ...interface{}) *StackError {
 			log.Printf("stackerr.Errorf must contain %%w")
 		}
 	}
-	return &StackError{error: fmt.Errorf(format, args...), St: st}
+	return finish(&StackError{error: fmt.Errorf(format, args...), St: st})
 }
 
 type StackError struct {
 	error
-	St FuncCallerInfo
+	St        FuncCallerInfo
+	Code      Code   // see WithCode
+	Class     Class  // see MarkRetryable
+	Goroutine int64  // see CaptureGoroutine
+	FullStack []byte // see WrapFatal
 }
 
 var _ error = (*StackError)(nil)
+var _ slog.LogValuer = (*StackError)(nil)
+
+// LogValue implements slog.LogValuer, so slog.Any("err", err) logs a group:
+//
+//	msg, func, file, and cause (same group, for wrapped StackErrors)
+func (s *StackError) LogValue() slog.Value {
+	if s == nil {
+		return slog.StringValue("<nil>")
+	}
+	attrs := []slog.Attr{
+		slog.String("msg", s.Error()),
+		slog.String("func", s.St.resolve().funcname),
+		slog.String("file", s.St.resolve().filetag),
+	}
+	if s.Code != CodeUnknown {
+		attrs = append(attrs, slog.String("code", s.Code.String()))
+	}
+	if s.Class != ClassNone {
+		attrs = append(attrs, slog.String("class", s.Class.String()))
+	}
+	if s.Goroutine != 0 {
+		attrs = append(attrs, slog.Int64("goroutine", s.Goroutine))
+	}
+	chld := new(StackError)
+	if errors.As(s.error, &chld) {
+		attrs = append(attrs, slog.Any("cause", chld))
+	}
+	return slog.GroupValue(attrs...)
+}
 
 func (s *StackError) Format(f fmt.State, c rune) {
 	switch c {
 	case 'v':
+		if f.Flag('#') {
+			s.goString(f)
+			return
+		}
+		if f.Flag(' ') {
+			fmt.Fprint(f, compact(s))
+			return
+		}
 		if f.Flag('+') {
 			fmt.Fprintf(f, "%+v", s.error)
-			fmt.Fprintf(f, "\n\tfrom %s", s.St.String())
+			if !s.St.skipped() {
+				fmt.Fprintf(f, "\n\tfrom %s", s.St.String())
+			}
+			if s.Goroutine != 0 {
+				fmt.Fprintf(f, " [goroutine %d]", s.Goroutine)
+			}
+			if len(s.FullStack) != 0 {
+				fmt.Fprintf(f, "\n\t%s", strings.ReplaceAll(strings.TrimSpace(string(s.FullStack)), "\n", "\n\t"))
+			}
 			//chl, ok := s.error.(*StackError)
 			chld := new(StackError)
 			if errors.As(s.error, &chld) { // recurse
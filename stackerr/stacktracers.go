@@ -27,17 +27,120 @@ import (
 	"runtime"
 	"runtime/debug"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
+// FuncCallerInfo is a captured call site.
+//
+// Only program counters are stored when capturing, symbolization (and FrameFilters) happens once, when first formatted.
 type FuncCallerInfo struct {
+	lazy *lazyFrame
+}
+
+// maxCallers stored per capture, FrameFilters can skip at most this many frames
+const maxCallers = 16
+
+type lazyFrame struct {
+	pcs      [maxCallers]uintptr
+	n        int
+	panicked bool // start after runtime.gopanic, see Recover
+	once     sync.Once
+	frame    resolvedFrame
+}
+
+type resolvedFrame struct {
 	funcname string
 	filetag  string
+	function string // full name, for FrameFilters
+	file     string
+	line     int
 }
 
+var unknownFrame = resolvedFrame{funcname: "unknown", filetag: "unknown:0"}
+
 func (fci FuncCallerInfo) String() string {
-	return fmt.Sprintf("%s (%s)", fci.funcname, fci.filetag)
+	fr := fci.resolve()
+	return fmt.Sprintf("%s (%s)", fr.funcname, fr.filetag)
+}
+
+// skipped by FrameFilters (when formatting)
+func (fci FuncCallerInfo) skipped() bool {
+	fr := fci.resolve()
+	return fr.function != "" && skipFrame(fr.function, fr.file)
 }
 
+// resolve symbolizes the call site once
+func (fci FuncCallerInfo) resolve() *resolvedFrame {
+	lf := fci.lazy
+	if lf == nil {
+		return &unknownFrame
+	}
+	lf.once.Do(func() {
+		lf.frame = unknownFrame
+		if fr, ok := pickFrame(lf.callers()); ok {
+			lf.frame = resolvedFrame{
+				funcname: filepath.Base(fr.Function),
+				filetag:  fmt.Sprintf("%s:%d", Cleanmodulepath(fr.File), fr.Line),
+				function: fr.Function,
+				file:     fr.File,
+				line:     fr.Line,
+			}
+		}
+	})
+	return &lf.frame
+}
+
+// pickFrame is the first frame not matched by FrameFilters (or the first frame)
+func pickFrame(candidates []runtime.Frame) (runtime.Frame, bool) {
+	if len(candidates) == 0 {
+		return runtime.Frame{}, false
+	}
+	for _, fr := range candidates {
+		if !skipFrame(fr.Function, fr.File) {
+			return fr, true
+		}
+	}
+	return candidates[0], true
+}
+
+// callers symbolizes all captured frames.
+// If panicked, frames up to runtime.gopanic and runtime frames after it are ignored, unless gopanic is missing.
+func (lf *lazyFrame) callers() []runtime.Frame {
+	var all []runtime.Frame
+	frames := runtime.CallersFrames(lf.pcs[:lf.n])
+	for {
+		fr, more := frames.Next()
+		if fr.PC != 0 {
+			all = append(all, fr)
+		}
+		if !more {
+			break
+		}
+	}
+	if !lf.panicked {
+		return all
+	}
+	for i, fr := range all {
+		if fr.Function == "runtime.gopanic" {
+			candidates := all[i+1:]
+			for len(candidates) > 1 && strings.HasPrefix(candidates[0].Function, "runtime.") {
+				candidates = candidates[1:]
+			}
+			return candidates
+		}
+	}
+	return all
+}
+
+// capture program counters, skip 0 is the caller of capture
+func capture(skip int, panicked bool) FuncCallerInfo {
+	lf := &lazyFrame{panicked: panicked}
+	lf.n = runtime.Callers(skip+2, lf.pcs[:])
+	return FuncCallerInfo{lazy: lf}
+}
+
+// GetFuncCallerInfo of the caller's caller (plus skips), moving up past frames matched by FrameFilters (when formatted)
 func GetFuncCallerInfo(skips ...int) FuncCallerInfo {
 	if len(skips) > 1 {
 		panic("GetFuncCallerInfo: too many skip arguments")
@@ -46,16 +149,7 @@ func GetFuncCallerInfo(skips ...int) FuncCallerInfo {
 	if len(skips) > 0 {
 		skip += skips[0]
 	}
-	pc, tfile, tline, ok := runtime.Caller(skip) // skip caller of this function
-	details := runtime.FuncForPC(pc)
-	if ok && details != nil {
-		tfile = Cleanmodulepath(tfile)
-		//fmt.Printf("called from %s (%s:%d)\n", filepath.Base(details.Name()), tfile, tline)
-	}
-	return FuncCallerInfo{
-		funcname: filepath.Base(details.Name()),
-		filetag:  fmt.Sprintf("%s:%d", tfile, tline),
-	}
+	return capture(skip, false) // skip caller of this function
 }
 
 // var mainmoduleprefix string
@@ -78,7 +172,28 @@ func init() {
 	mainpwd = dir
 	mainmodulecmdprefix = buildinfo.Main.Path
 }
+
+// custom prefixes, see SetPathTrimming
+var trimprefixes atomic.Pointer[[]string]
+
+// SetPathTrimming replaces the default path trimming (main module path and working directory, detected at init)
+// with prefixes, for binaries run from other directories or built elsewhere. The longest matching prefix is trimmed.
+//
+// Safe to call at any time, call with no prefixes to restore the default. Call sites already formatted keep their paths.
+func SetPathTrimming(prefixes ...string) {
+	if len(prefixes) == 0 {
+		trimprefixes.Store(nil)
+		return
+	}
+	p := append([]string(nil), prefixes...)
+	trimprefixes.Store(&p)
+}
+
+// Cleanmodulepath shortens p for display, see SetPathTrimming
 func Cleanmodulepath(p string) string {
+	if prefixes := trimprefixes.Load(); prefixes != nil {
+		return trimLongest(p, *prefixes)
+	}
 	p1 := p
 	p = strings.TrimPrefix(p, mainmodulecmdprefix)
 	if mainpwd != "/" {
@@ -93,3 +208,19 @@ func Cleanmodulepath(p string) string {
 	}
 	return p
 }
+
+func trimLongest(p string, prefixes []string) string {
+	best := ""
+	for _, prefix := range prefixes {
+		if len(prefix) > len(best) && strings.HasPrefix(p, prefix) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return p
+	}
+	if trimmed := strings.TrimPrefix(p[len(best):], "/"); trimmed != "" {
+		return trimmed
+	}
+	return p
+}
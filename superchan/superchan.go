@@ -11,6 +11,7 @@ import (
 
 	"github.com/aerth/mostly/cancellable"
 	"github.com/aerth/mostly/flagpkg"
+	"github.com/aerth/mostly/stackerr"
 )
 
 var Log = log.Default()
@@ -57,12 +58,13 @@ type Superchan[T any] struct {
 	cancellable.Chan[T]
 	deferfuncs            []func() // starts as non-nil empty array
 	deferlast, deferfirst func()
+	deferctx              context.Context // set by rundeferred, see DeferCtx
 }
 
 type Main = Superchan[os.Signal]
 
-// DoneWaitSeconds is the number of seconds to wait for deferred funcs to finish after context is done
-// Only used in Wait() function.
+// MaxWaitDuration to wait for deferred funcs to finish after context is done
+// Used by Wait() and as the DeferCtx timeout.
 var MaxWaitDuration = time.Second * 5
 
 func (s *Superchan[T]) IsDead() bool {
@@ -100,6 +102,18 @@ func (s *Superchan[T]) Defer(f ...func()) {
 	}
 }
 
+// DeferCtx is Defer for funcs that can block (network, disk), ctx is done after MaxWaitDuration
+// (counted from when deferred funcs start running), so they can give up instead of hanging forever.
+//
+// ctx keeps the values of this context but not its cancellation (which already happened).
+//
+//	s.DeferCtx(func(ctx context.Context) { server.Shutdown(ctx) })
+func (s *Superchan[T]) DeferCtx(f ...func(ctx context.Context)) {
+	for _, ff := range f {
+		s.Defer(func() { ff(s.deferctx) })
+	}
+}
+
 // DeferFirst is called first after context is finished.
 //
 // Could be a call to http.Shutdown, for example
@@ -140,6 +154,9 @@ func (s *Superchan[T]) rundeferred() {
 		panic("rundeferred called twice")
 	}
 	//Log.Printf("running deferred funcs: parallel=%v", UseGoroutineDefer)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(s.Chan), MaxWaitDuration)
+	defer cancel()
+	s.deferctx = ctx // before any deferred func runs
 	var wg sync.WaitGroup
 	caller := func(fn func()) {
 		fn() // call directly
@@ -150,8 +167,8 @@ func (s *Superchan[T]) rundeferred() {
 			go func() {
 				defer wg.Done() // last deferred
 				defer func() {
-					if r := recover(); r != nil {
-						Log.Printf("error in deferred func (panic): %v", r)
+					if err := stackerr.Recover(recover()); err != nil {
+						Log.Printf("error in deferred func: %+v", err)
 					}
 				}()
 				fn()
@@ -219,6 +236,7 @@ func mainSignalHandler(chctx *Superchan[os.Signal]) {
 // Reads from the channel and calls the handler func for every update. Send to chctx.Ch(), cancel with chctx.Cancel(err).
 //
 // The handler func should return nil error unless you want the context cancelled, (stopping the reader loop).
+// Deferred funcs run once the loop stops, however the context was cancelled (including by a handler error).
 func New[T any](parent context.Context, handler func(context.Context, T) error, parallel bool) *Superchan[T] {
 	if handler == nil {
 		panic("superchan: no handler provided")
@@ -244,6 +262,9 @@ func New[T any](parent context.Context, handler func(context.Context, T) error,
 			}
 
 		}
+		if !chctx.IsDead() { // cancelled while in handler
+			chctx.rundeferred()
+		}
 	}()
 	return chctx
 }
@@ -0,0 +1,58 @@
+package superchan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeferCtx(t *testing.T) {
+	old := MaxWaitDuration
+	MaxWaitDuration = 50 * time.Millisecond
+	defer func() { MaxWaitDuration = old }()
+	type key struct{}
+	s := NewRaw[int](context.WithValue(context.Background(), key{}, "v"))
+	result := make(chan error, 1)
+	s.DeferCtx(func(ctx context.Context) {
+		if ctx.Value(key{}) != "v" {
+			result <- errors.New("lost context values")
+			return
+		}
+		if ctx.Err() != nil {
+			result <- errors.New("done before budget")
+			return
+		}
+		select {
+		case <-ctx.Done():
+			result <- nil
+		case <-time.After(time.Second):
+			result <- errors.New("budget not enforced")
+		}
+	})
+	s.Cancel(errors.New("stop"))
+	s.rundeferred()
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsDead() {
+		t.Fatal("not dead after rundeferred")
+	}
+}
+
+func TestNewHandlerCancelRunsDeferred(t *testing.T) {
+	s := New(context.Background(), func(context.Context, int) error {
+		return errors.New("stop")
+	}, false)
+	ran := make(chan struct{})
+	s.Defer(func() { close(ran) })
+	s.Ch() <- 1
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("deferred func not run after handler error")
+	}
+	if err := context.Cause(s); err == nil || err.Error() != "stop" {
+		t.Fatalf("cause: %v", err)
+	}
+}
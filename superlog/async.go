@@ -0,0 +1,144 @@
+package superlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aerth/mostly/superchan"
+)
+
+// AsyncOptions for NewAsync
+type AsyncOptions struct {
+	BatchSize     int           // bytes buffered before writing, 0 writes each entry separately (use 0 for journald and syslog, one entry per write)
+	FlushInterval time.Duration // when batching, flush at least this often (default 1s)
+}
+
+// Async is an io.Writer queueing writes to a background goroutine, see NewAsync
+type Async struct {
+	w    io.Writer
+	opts AsyncOptions
+	sc   *superchan.Superchan[[]byte]
+
+	sendmu sync.RWMutex // Write holds R while queueing, the final drain holds W
+	mu     sync.Mutex   // guards buf and w
+	buf    bytes.Buffer
+	done   chan struct{} // closed after the final drain
+}
+
+var _ io.Writer = (*Async)(nil)
+
+// NewAsync writer for high throughput logging. Write copies b and returns without waiting for w.
+//
+// When ctx is cancelled, queued entries are written and flushed (use Wait to block until then),
+// later writes wait for that, then go directly to w.
+func NewAsync(ctx context.Context, w io.Writer, opts AsyncOptions) *Async {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	a := &Async{w: w, opts: opts, done: make(chan struct{})}
+	a.sc = superchan.New(ctx, func(_ context.Context, b []byte) error {
+		a.write(b)
+		return nil
+	}, false)
+	if a.sc.Err() != nil { // ctx already done, nothing to drain
+		close(a.done)
+		return a
+	}
+	a.sc.Defer(a.drain)
+	if opts.BatchSize > 0 {
+		go a.flusher()
+	}
+	return a
+}
+
+// Write queues a copy of b (written directly once the context is done and the queue is drained)
+func (a *Async) Write(b []byte) (int, error) {
+	b = bytes.Clone(b)
+	if a.send(b) {
+		return len(b), nil
+	}
+	<-a.done // after the queued entries
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.flushLocked(); err != nil {
+		return 0, err
+	}
+	return a.w.Write(b)
+}
+
+// send queues b, false if the context is done
+func (a *Async) send(b []byte) bool {
+	a.sendmu.RLock()
+	defer a.sendmu.RUnlock()
+	if a.sc.Err() != nil {
+		return false
+	}
+	select {
+	case a.sc.Ch() <- b:
+		return true
+	case <-a.sc.Done():
+		return false
+	}
+}
+
+// Flush buffered entries to the underlying writer
+func (a *Async) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.flushLocked()
+}
+
+// Wait until the context is done and queued entries are written
+func (a *Async) Wait() error {
+	<-a.done
+	return context.Cause(a.sc)
+}
+
+func (a *Async) write(b []byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.opts.BatchSize <= 0 {
+		a.w.Write(b)
+		return
+	}
+	a.buf.Write(b)
+	if a.buf.Len() >= a.opts.BatchSize {
+		a.flushLocked()
+	}
+}
+
+func (a *Async) flushLocked() error {
+	if a.buf.Len() == 0 {
+		return nil
+	}
+	_, err := a.w.Write(a.buf.Bytes())
+	a.buf.Reset()
+	return err
+}
+
+// drain (deferred) writes everything still queued, then flushes
+func (a *Async) drain() {
+	defer close(a.done)
+	a.sendmu.Lock()
+	defer a.sendmu.Unlock()
+	for _, b := range a.sc.Updates2() {
+		a.write(b)
+	}
+	a.Flush()
+}
+
+func (a *Async) flusher() {
+	t := time.NewTicker(a.opts.FlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-a.sc.Done():
+			return
+		case <-t.C:
+			a.Flush()
+		}
+	}
+}
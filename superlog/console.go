@@ -0,0 +1,91 @@
+package superlog
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aerth/mostly/journalwriter"
+)
+
+// ConsoleOptions for NewConsole
+type ConsoleOptions struct {
+	Color bool // ANSI colors by priority
+	Time  bool // short "15:04:05.000" timestamp prefix (leave false if using log's timestamps)
+}
+
+var consoleColors = map[journalwriter.Priority]string{
+	journalwriter.PriEmerg:   "\033[1;31m",
+	journalwriter.PriAlert:   "\033[1;31m",
+	journalwriter.PriCrit:    "\033[1;31m",
+	journalwriter.PriErr:     "\033[31m",
+	journalwriter.PriWarning: "\033[33m",
+	journalwriter.PriNotice:  "\033[36m",
+	journalwriter.PriDebug:   "\033[2m",
+}
+
+// IsTerminal if f is a character device (a TTY), and NO_COLOR is not set
+func IsTerminal(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	st, err := f.Stat()
+	return err == nil && st.Mode()&os.ModeCharDevice != 0
+}
+
+// UseConsoleFallback sets journalwriter.FallbackWriter to Stderr(), if it is still os.Stderr.
+// Call it from main to color journalwriter output when the journal is not available, like New's.
+func UseConsoleFallback() {
+	if journalwriter.FallbackWriter == io.Writer(os.Stderr) {
+		journalwriter.FallbackWriter = Stderr()
+	}
+}
+
+// Stderr is a colored console if stderr is a terminal, otherwise os.Stderr.
+// Used for every stderr output and fallback of New and NewWithOptions, see UseConsoleFallback.
+func Stderr() io.Writer {
+	if IsTerminal(os.Stderr) {
+		return NewConsole(os.Stderr, ConsoleOptions{Color: true})
+	}
+	return os.Stderr
+}
+
+// NewConsole formats lines for humans, the priority is detected with journalwriter.ParsePriority
+func NewConsole(w io.Writer, opts ConsoleOptions) io.Writer {
+	return &console{w: w, opts: opts}
+}
+
+type console struct {
+	w    io.Writer
+	opts ConsoleOptions
+	mu   sync.Mutex
+	buf  []byte
+}
+
+func (c *console) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	line := c.buf[:0]
+	if c.opts.Time {
+		line = time.Now().AppendFormat(line, "15:04:05.000 ")
+	}
+	color := ""
+	if c.opts.Color {
+		p := linePriority(b, journalwriter.PriInfo) // no color
+		color = consoleColors[p]
+	}
+	msg := bytes.TrimRight(b, "\n")
+	if color != "" {
+		line = append(append(append(line, color...), msg...), "\033[0m"...)
+	} else {
+		line = append(line, msg...)
+	}
+	line = append(line, '\n')
+	c.buf = line
+	if _, err := c.w.Write(line); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
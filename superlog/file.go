@@ -0,0 +1,195 @@
+package superlog
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileOptions for OpenFile
+type FileOptions struct {
+	Path       string
+	MaxSize    int64         // rotate before exceeding this many bytes, 0 for no limit
+	MaxAge     time.Duration // rotate when the file is older, 0 for no limit
+	MaxBackups int           // rotated files to keep, 0 keeps all
+	Compress   bool          // gzip rotated files
+	Mode       os.FileMode   // default 0640
+
+	// OnError is called when rotating, compressing or pruning fails during Write (the line is still written), default prints to stderr
+	OnError func(error)
+}
+
+// RotatingFile is an io.Writer appending to a file, rotated by size and age.
+//
+// Rotated files are named path.20060102-150405.000000 (plus .gz if compressed).
+// The age of an existing file is counted from its modification time.
+type RotatingFile struct {
+	opts  FileOptions
+	mu    sync.Mutex
+	f     *os.File
+	size  int64
+	since time.Time // for MaxAge
+}
+
+var _ io.WriteCloser = (*RotatingFile)(nil)
+
+// OpenFile for appending, creating directories as needed
+func OpenFile(opts FileOptions) (*RotatingFile, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("superlog: no file path")
+	}
+	if opts.Mode == 0 {
+		opts.Mode = 0640
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(err error) { fmt.Fprintf(Stderr(), "superlog: %s: %v\n", opts.Path, err) }
+	}
+	r := &RotatingFile{opts: opts}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.opts.Path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.opts.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, r.opts.Mode)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size, r.since = f, st.Size(), time.Now()
+	if st.Size() > 0 {
+		r.since = st.ModTime()
+	}
+	return nil
+}
+
+// Write b, rotating first if needed
+func (r *RotatingFile) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+	tooBig := r.opts.MaxSize > 0 && r.size > 0 && r.size+int64(len(b)) > r.opts.MaxSize
+	tooOld := r.opts.MaxAge > 0 && r.size > 0 && time.Since(r.since) >= r.opts.MaxAge
+	var rotateErr error
+	if tooBig || tooOld {
+		if rotateErr = r.rotate(); r.f == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := r.f.Write(b)
+	r.size += int64(n)
+	if rotateErr != nil {
+		r.opts.OnError(rotateErr) // after writing the line
+	}
+	return n, err
+}
+
+// Rotate now
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return os.ErrClosed
+	}
+	return r.rotate()
+}
+
+// Close the file, further writes fail
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// rotate renames the file and opens a new one, or reopens the old one if that fails (r.f is nil only if both fail).
+// Errors compressing or pruning backups are returned with the new file open.
+func (r *RotatingFile) rotate() error {
+	r.f.Close()
+	r.f = nil
+	backup := r.opts.Path + "." + time.Now().Format("20060102-150405.000000")
+	if err := os.Rename(r.opts.Path, backup); err != nil {
+		return errors.Join(err, r.open())
+	}
+	if err := r.open(); err != nil {
+		return errors.Join(err, r.reopen(backup))
+	}
+	if r.opts.Compress {
+		if err := gzipFile(backup); err != nil {
+			return err
+		}
+	}
+	return r.prune()
+}
+
+// reopen the renamed file in place (append mode), when the new file can't be created
+func (r *RotatingFile) reopen(backup string) error {
+	if err := os.Rename(backup, r.opts.Path); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// prune backups beyond MaxBackups, oldest first
+func (r *RotatingFile) prune() error {
+	if r.opts.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(r.opts.Path + ".*")
+	if err != nil {
+		return err
+	}
+	sort.Strings(backups) // timestamp names sort by age
+	for len(backups) > r.opts.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,143 @@
+package superlog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerth/mostly/journalwriter"
+)
+
+// GELFOptions for DialGELF
+type GELFOptions struct {
+	Network   string                 // "udp" (default) or "tcp"
+	Addr      string                 // host:12201
+	Host      string                 // default os.Hostname
+	Compress  bool                   // gzip udp messages
+	ChunkSize int                    // max udp datagram, default 1420 (use 8154 on a LAN)
+	Extra     map[string]any         // additional fields, sent as _key
+	Priority  journalwriter.Priority // level of lines without a detected level (default INFO)
+	Timeout   time.Duration          // dial timeout, default 10s
+}
+
+// GELFWriter sends each write as a GELF 1.1 message (Graylog), see DialGELF
+type GELFWriter struct {
+	opts GELFOptions
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// maximum chunks per udp message (GELF spec)
+const gelfMaxChunks = 128
+
+// DialGELF connects to a Graylog GELF input
+func DialGELF(opts GELFOptions) (*GELFWriter, error) {
+	if opts.Network == "" {
+		opts.Network = "udp"
+	}
+	if opts.Network != "udp" && opts.Network != "tcp" {
+		return nil, fmt.Errorf("superlog: unsupported gelf network: %q", opts.Network)
+	}
+	if opts.Host == "" {
+		opts.Host, _ = os.Hostname()
+	}
+	if opts.ChunkSize <= 12 {
+		opts.ChunkSize = 1420
+	}
+	if opts.Priority == 0 {
+		opts.Priority = journalwriter.PriInfo
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	conn, err := net.DialTimeout(opts.Network, opts.Addr, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &GELFWriter{opts: opts, conn: conn}, nil
+}
+
+// Write b as one message (first line is short_message)
+func (g *GELFWriter) Write(b []byte) (int, error) {
+	p := linePriority(b, g.opts.Priority)
+	msg, err := g.encode(time.Now(), p, string(bytes.TrimRight(b, "\n")))
+	if err != nil {
+		return 0, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.opts.Network == "tcp" {
+		_, err = g.conn.Write(append(msg, 0)) // null byte delimited, uncompressed
+	} else {
+		err = g.sendUDP(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close the connection
+func (g *GELFWriter) Close() error {
+	return g.conn.Close()
+}
+
+func (g *GELFWriter) encode(t time.Time, p journalwriter.Priority, msg string) ([]byte, error) {
+	m := make(map[string]any, len(g.opts.Extra)+6)
+	for k, v := range g.opts.Extra {
+		if k == "" || k == "id" || k == "_id" {
+			continue // reserved
+		}
+		m["_"+strings.TrimPrefix(k, "_")] = v
+	}
+	short, _, multiline := strings.Cut(msg, "\n")
+	m["version"] = "1.1"
+	m["host"] = g.opts.Host
+	m["short_message"] = short
+	if multiline {
+		m["full_message"] = msg
+	}
+	m["timestamp"] = float64(t.UnixMicro()) / 1e6
+	m["level"] = int(p)
+	return json.Marshal(m)
+}
+
+func (g *GELFWriter) sendUDP(msg []byte) error {
+	if g.opts.Compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(msg)
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		msg = buf.Bytes()
+	}
+	if len(msg) <= g.opts.ChunkSize {
+		_, err := g.conn.Write(msg)
+		return err
+	}
+	size := g.opts.ChunkSize - 12 // chunk header
+	count := (len(msg) + size - 1) / size
+	if count > gelfMaxChunks {
+		return fmt.Errorf("superlog: gelf message too large: %d bytes", len(msg))
+	}
+	var id [8]byte
+	rand.Read(id[:])
+	for i := 0; i < count; i++ {
+		end := min((i+1)*size, len(msg))
+		chunk := append([]byte{0x1e, 0x0f}, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, msg[i*size:end]...)
+		if _, err := g.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package superlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/aerth/mostly/journalwriter"
+	"github.com/aerth/mostly/superchan"
+)
+
+// NewLevelFilter drops lines less severe than level, which can be changed at any time (see HandleLevelSignal).
+//
+// The priority of a line is detected with journalwriter.ParsePriority, or p (INFO if zero). A nil level writes everything.
+func NewLevelFilter(w io.Writer, p journalwriter.Priority, level *journalwriter.PriorityVar) io.Writer {
+	if p == 0 {
+		p = journalwriter.PriInfo
+	}
+	return levelFilter{w: w, p: p, level: level}
+}
+
+type levelFilter struct {
+	w     io.Writer
+	p     journalwriter.Priority
+	level *journalwriter.PriorityVar
+}
+
+func (f levelFilter) Write(b []byte) (int, error) {
+	if !f.level.Enabled(linePriority(b, f.p)) {
+		return len(b), nil
+	}
+	return f.w.Write(b)
+}
+
+// linePriority is the priority detected in b (see journalwriter.ParsePriority), or p if none
+func linePriority(b []byte, p journalwriter.Priority) journalwriter.Priority {
+	if detected, ok := journalwriter.ParsePriority(b); ok {
+		p = detected
+	}
+	return p
+}
+
+// HandleLevelSignal toggles debug logging on each signal (eg: syscall.SIGUSR1) until ctx is done:
+// the first signal sets level to PriDebug, the next restores the priority set before it. Changes are logged to w (nil for Stderr).
+//
+//	level := journalwriter.NewPriorityVar(journalwriter.PriInfo)
+//	w, err := superlog.NewWithOptions(superlog.Options{MinPriority: level, ...})
+//	superlog.HandleLevelSignal(mainctx, level, w, syscall.SIGUSR1)
+func HandleLevelSignal(ctx context.Context, level *journalwriter.PriorityVar, w io.Writer, sig ...os.Signal) *superchan.Superchan[os.Signal] {
+	if w == nil {
+		w = Stderr()
+	}
+	var (
+		mu     sync.Mutex
+		normal = level.Priority()
+	)
+	sc := superchan.New(ctx, func(_ context.Context, s os.Signal) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if cur := level.Priority(); cur != journalwriter.PriDebug {
+			normal = cur
+			level.Set(journalwriter.PriDebug)
+		} else {
+			level.Set(normal)
+		}
+		fmt.Fprintf(w, "superlog: caught %v, log level is now %s\n", s, level)
+		return nil
+	}, false)
+	if sc.Err() != nil { // ctx already done, can't Defer
+		return sc
+	}
+	signal.Notify(sc.Ch(), sig...)
+	sc.Defer(func() { signal.Stop(sc.Ch()) })
+	return sc
+}
+
+// VerbosityPriority is base lowered by n levels (eg: a flagpkg.CountVar "-v" count), at most PriDebug
+//
+//	level.Set(superlog.VerbosityPriority(journalwriter.PriNotice, verbosity))
+func VerbosityPriority(base journalwriter.Priority, n int) journalwriter.Priority {
+	if n <= 0 {
+		return base
+	}
+	return min(base+journalwriter.Priority(min(n, int(journalwriter.PriDebug))), journalwriter.PriDebug)
+}
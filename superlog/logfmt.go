@@ -0,0 +1,69 @@
+package superlog
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/aerth/mostly/journalwriter"
+)
+
+// Format of a Destination
+type Format int
+
+const (
+	FormatRaw    Format = iota // lines as written
+	FormatLogfmt               // time=... level=... msg="..."
+)
+
+// Logfmt wraps w, each write becomes one logfmt line: time=2006-01-02T15:04:05Z07:00 level=info msg="..."
+//
+// The level is detected with journalwriter.ParsePriority, or p (INFO if zero). Lines already containing level= and msg= pass through.
+func Logfmt(w io.Writer, p journalwriter.Priority) io.Writer {
+	if p == 0 {
+		p = journalwriter.PriInfo
+	}
+	return logfmtWriter{w: w, p: p}
+}
+
+type logfmtWriter struct {
+	w io.Writer
+	p journalwriter.Priority
+}
+
+func (l logfmtWriter) Write(b []byte) (int, error) {
+	p := linePriority(b, l.p)
+	if _, err := l.w.Write(AppendLogfmt(nil, time.Now(), p, b)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// AppendLogfmt appends msg as a logfmt line (newline terminated)
+func AppendLogfmt(dst []byte, t time.Time, p journalwriter.Priority, msg []byte) []byte {
+	msg = bytes.TrimRight(msg, "\n")
+	if bytes.Contains(msg, []byte("level=")) && bytes.Contains(msg, []byte("msg=")) {
+		return append(append(dst, msg...), '\n')
+	}
+	dst = append(dst, "time="...)
+	dst = t.AppendFormat(dst, time.RFC3339)
+	dst = append(dst, " level="...)
+	dst = append(dst, p.String()...)
+	dst = append(dst, " msg="...)
+	dst = appendLogfmtValue(dst, string(msg))
+	return append(dst, '\n')
+}
+
+// appendLogfmtValue quotes values containing spaces, quotes, '=' or control characters
+func appendLogfmtValue(dst []byte, v string) []byte {
+	if v == "" {
+		return append(dst, `""`...)
+	}
+	for _, c := range v {
+		if c <= ' ' || c == '"' || c == '=' || c == 0x7f {
+			return strconv.AppendQuote(dst, v)
+		}
+	}
+	return append(dst, v...)
+}
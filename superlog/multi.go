@@ -0,0 +1,56 @@
+package superlog
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/aerth/mostly/journalwriter"
+)
+
+// Destination for NewMulti
+type Destination struct {
+	io.Writer
+	MaxPriority journalwriter.Priority // least severe priority written (eg: PriWarning for warnings and worse), zero writes everything
+	Format      Format                 // FormatRaw (default) or FormatLogfmt (not for journald)
+}
+
+// Multi writes each line to every Destination allowing its priority, see NewMulti
+type Multi struct {
+	p     journalwriter.Priority
+	dests []Destination
+}
+
+var _ io.Writer = (*Multi)(nil)
+
+// NewMulti fans out writes to several destinations (journald + file + stderr), each with its own level.
+//
+// The priority of a line is detected with journalwriter.ParsePriority, or p (INFO if zero).
+// journalwriter.JournalWriter destinations receive entries at the detected priority.
+func NewMulti(p journalwriter.Priority, dests ...Destination) *Multi {
+	if p == 0 {
+		p = journalwriter.PriInfo
+	}
+	return &Multi{p: p, dests: dests}
+}
+
+// Write b to each allowed destination, errors are joined (b is considered written)
+func (m *Multi) Write(b []byte) (int, error) {
+	p := linePriority(b, m.p)
+	var errs []error
+	for _, d := range m.dests {
+		if d.MaxPriority != 0 && p > d.MaxPriority {
+			continue
+		}
+		w, line := d.Writer, b
+		if _, ok := w.(journalwriter.JournalWriter); ok {
+			w = journalwriter.JournalWriter{Priority: p}
+		} else if d.Format == FormatLogfmt {
+			line = AppendLogfmt(nil, time.Now(), p, b)
+		}
+		if _, err := w.Write(line); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(b), errors.Join(errs...)
+}
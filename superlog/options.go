@@ -0,0 +1,176 @@
+package superlog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/syslog"
+	"os"
+	"path/filepath"
+
+	"github.com/aerth/mostly/journalwriter"
+)
+
+// SinkOptions common to every sink of NewWithOptions
+type SinkOptions struct {
+	MaxPriority journalwriter.Priority // least severe priority written, zero writes everything (see Destination)
+	Format      Format                 // ignored by journald, syslog and gelf (structured already)
+}
+
+// LocalSyslogOptions for the local syslog daemon sink of NewWithOptions
+type LocalSyslogOptions struct {
+	SinkOptions
+	Facility syslog.Priority // default LOG_DAEMON
+}
+
+// Options for NewWithOptions. A nil sink is disabled.
+type Options struct {
+	Priority    journalwriter.Priority     // lines without a detected level (default INFO)
+	MinPriority *journalwriter.PriorityVar // drops less severe lines for the returned writer, nil writes everything (see HandleLevelSignal)
+
+	Journald    *SinkOptions
+	LocalSyslog *LocalSyslogOptions
+	Syslog      *SyslogOptions // remote
+	SyslogSink  SinkOptions
+	GELF        *GELFOptions
+	GELFSink    SinkOptions
+	File        *FileOptions
+	FileSink    SinkOptions
+	Stderr      *SinkOptions  // colored if a terminal, see Stderr. Used alone when no sink is set.
+	Ring        *Ring         // recent entries for an admin page, see NewRing
+	Extra       []Destination // any other writers
+
+	Repeat *RepeatOptions // suppress repeated lines, see NewRepeatLimiter
+	Async  *AsyncOptions  // write in the background, flushed when Context is done
+	// Context for Async (required with Async)
+	Context context.Context
+
+	// Fallback replaces a sink that fails to open (nil uses Stderr()), the error is still returned
+	Fallback io.Writer
+}
+
+// NewWithOptions returns a non-nil writer to every configured sink, errors opening sinks are joined.
+//
+// Close it to flush and close the sinks it opened (file, syslog, gelf). With Async, Close first waits for Context to be done.
+//
+//	w, err := superlog.NewWithOptions(superlog.Options{
+//		Journald: &superlog.SinkOptions{},
+//		File:     &superlog.FileOptions{Path: "/var/log/app.log", MaxSize: 100 << 20, MaxBackups: 5, Compress: true},
+//		FileSink: superlog.SinkOptions{Format: superlog.FormatLogfmt},
+//	})
+//	defer w.Close()
+func NewWithOptions(opts Options) (io.WriteCloser, error) {
+	p := opts.Priority
+	if p == 0 {
+		p = journalwriter.PriInfo
+	}
+	fallback := opts.Fallback
+	if fallback == nil {
+		fallback = Stderr()
+	}
+	var (
+		dests []Destination
+		errs  []error
+		out   = &sinks{}
+	)
+	add := func(w io.Writer, err error, sink SinkOptions) {
+		if err != nil {
+			errs = append(errs, err)
+			w = fallback
+		}
+		dests = append(dests, Destination{Writer: w, MaxPriority: sink.MaxPriority, Format: sink.Format})
+	}
+	open := func(w io.WriteCloser, err error, sink SinkOptions) {
+		if err == nil {
+			out.closers = append(out.closers, w)
+		}
+		add(w, err, sink)
+	}
+	if opts.Journald != nil {
+		if journalwriter.Enabled() {
+			add(journalwriter.JournalWriter{Priority: p}, nil, *opts.Journald)
+		} else {
+			add(nil, errors.New("journal not enabled"), *opts.Journald)
+		}
+	}
+	if opts.LocalSyslog != nil {
+		lo := *opts.LocalSyslog
+		if lo.Facility == 0 {
+			lo.Facility = syslog.LOG_DAEMON
+		}
+		w, err := syslog.Dial("", "", syslog.LOG_DEBUG|lo.Facility, filepath.Base(os.Args[0]))
+		open(w, err, lo.SinkOptions)
+	}
+	if opts.Syslog != nil {
+		so := *opts.Syslog
+		if so.Priority == 0 {
+			so.Priority = p
+		}
+		w, err := DialSyslog(so)
+		open(w, err, opts.SyslogSink)
+	}
+	if opts.GELF != nil {
+		gopts := *opts.GELF
+		if gopts.Priority == 0 {
+			gopts.Priority = p
+		}
+		w, err := DialGELF(gopts)
+		open(w, err, opts.GELFSink)
+	}
+	if opts.File != nil {
+		w, err := OpenFile(*opts.File)
+		open(w, err, opts.FileSink)
+	}
+	if opts.Ring != nil {
+		add(opts.Ring, nil, SinkOptions{})
+	}
+	dests = append(dests, opts.Extra...)
+	if opts.Stderr != nil || len(dests) == 0 {
+		sink := SinkOptions{}
+		if opts.Stderr != nil {
+			sink = *opts.Stderr
+		}
+		add(Stderr(), nil, sink)
+	}
+	var w io.Writer = NewMulti(p, dests...)
+	if opts.Repeat != nil {
+		out.repeat = NewRepeatLimiter(w, *opts.Repeat)
+		w = out.repeat
+	}
+	if opts.Async != nil {
+		if opts.Context == nil {
+			errs = append(errs, errors.New("superlog: Async requires Context"))
+		} else {
+			out.async = NewAsync(opts.Context, w, *opts.Async)
+			w = out.async
+		}
+	}
+	if opts.MinPriority != nil {
+		w = NewLevelFilter(w, p, opts.MinPriority)
+	}
+	out.Writer = w
+	return out, errors.Join(errs...)
+}
+
+// sinks is the NewWithOptions writer
+type sinks struct {
+	io.Writer
+	repeat  *RepeatLimiter
+	async   *Async
+	closers []io.Closer // opened by NewWithOptions
+}
+
+// Close waits for Async (if any), flushes repeated lines, then closes opened sinks
+func (s *sinks) Close() error {
+	if s.async != nil {
+		s.async.Wait()
+	}
+	var errs []error
+	if s.repeat != nil {
+		errs = append(errs, s.repeat.Flush())
+	}
+	for _, c := range s.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
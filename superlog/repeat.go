@@ -0,0 +1,104 @@
+package superlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// RepeatOptions for NewRepeatLimiter
+type RepeatOptions struct {
+	Window time.Duration // identical lines within this window are suppressed (default 10s)
+	Sample int           // write every Nth repeat anyway, 0 suppresses all repeats
+	Prefix string        // log.Logger prefix, ignored when comparing lines
+}
+
+// RepeatLimiter suppresses identical consecutive lines, writing "last message repeated N times" instead,
+// to protect journald/syslog from log storms (eg: an error in a tight loop).
+//
+// Lines are compared without a leading log.Logger timestamp (Ldate, Ltime, Lmicroseconds) or Prefix,
+// so it can wrap a log.Logger output with the default LstdFlags. Other timestamps must not be added.
+type RepeatLimiter struct {
+	w    io.Writer
+	opts RepeatOptions
+
+	mu         sync.Mutex
+	last       []byte
+	since      time.Time
+	repeats    int // since last was written
+	suppressed int // not written
+}
+
+var _ io.Writer = (*RepeatLimiter)(nil)
+
+// NewRepeatLimiter wraps w
+func NewRepeatLimiter(w io.Writer, opts RepeatOptions) *RepeatLimiter {
+	if opts.Window <= 0 {
+		opts.Window = 10 * time.Second
+	}
+	return &RepeatLimiter{w: w, opts: opts}
+}
+
+// Write b, unless it repeats the last line within the window
+func (r *RepeatLimiter) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	key := repeatKey(b, r.opts.Prefix)
+	if r.last != nil && bytes.Equal(key, r.last) && now.Sub(r.since) < r.opts.Window {
+		r.repeats++
+		if r.opts.Sample > 0 && r.repeats%r.opts.Sample == 0 {
+			return r.w.Write(b)
+		}
+		r.suppressed++
+		return len(b), nil
+	}
+	if err := r.flushLocked(); err != nil {
+		return 0, err
+	}
+	r.last, r.since, r.repeats = bytes.Clone(key), now, 0
+	return r.w.Write(b)
+}
+
+// Flush writes the pending "last message repeated" line, if any
+func (r *RepeatLimiter) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flushLocked()
+}
+
+func (r *RepeatLimiter) flushLocked() error {
+	if r.suppressed == 0 {
+		return nil
+	}
+	n := r.suppressed
+	r.suppressed = 0
+	_, err := fmt.Fprintf(r.w, "last message repeated %d times\n", n)
+	return err
+}
+
+// repeatKey is b without the log.Logger prefix and header, the prefix is before the header or after it (Lmsgprefix)
+func repeatKey(b []byte, prefix string) []byte {
+	b = bytes.TrimPrefix(b, []byte(prefix))
+	b = skipDigits(b, "0000/00/00 ")
+	if rest := skipDigits(b, "00:00:00"); len(rest) != len(b) {
+		b = skipDigits(rest, ".000000")
+		b = bytes.TrimPrefix(b, []byte(" "))
+	}
+	return bytes.TrimPrefix(b, []byte(prefix))
+}
+
+// skipDigits returns b after pattern ('0' is any digit), or b if it does not match
+func skipDigits(b []byte, pattern string) []byte {
+	if len(b) < len(pattern) {
+		return b
+	}
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '0' && (b[i] < '0' || b[i] > '9') || pattern[i] != '0' && b[i] != pattern[i] {
+			return b
+		}
+	}
+	return b[len(pattern):]
+}
@@ -0,0 +1,36 @@
+package superlog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/aerth/mostly/httpserver/httpctx"
+)
+
+// RequestLogger with request_id, method, path and client_ip (see httpctx.GetClientIP) attrs. h nil uses slog.Default().
+//
+// For journald use journalwriter.NewSlogHandler (attrs become REQUEST_ID, METHOD, ...)
+func RequestLogger(r *http.Request, h slog.Handler) *slog.Logger {
+	if h == nil {
+		h = slog.Default().Handler()
+	}
+	return slog.New(h).With(
+		slog.Int("request_id", httpctx.GetUUID(r.Context())),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("client_ip", httpctx.GetClientIP(r).String()),
+	)
+}
+
+// RequestLoggerMiddleware stores a RequestLogger in each request's context, retrieve with httpctx.GetLogger.
+//
+//	server.InsertMiddleware(superlog.RequestLoggerMiddleware(journalwriter.NewSlogHandler(nil)))
+func RequestLoggerMiddleware(h slog.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), httpctx.KLogger, RequestLogger(r, h))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
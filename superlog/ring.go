@@ -0,0 +1,162 @@
+package superlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerth/mostly/anydb"
+	"github.com/aerth/mostly/httpserver"
+	"github.com/aerth/mostly/journalwriter"
+	"go.etcd.io/bbolt"
+)
+
+// Entry retained by a Ring
+type Entry struct {
+	Time     time.Time              `json:"time"`
+	Priority journalwriter.Priority `json:"priority"`
+	Level    string                 `json:"level"`
+	Message  string                 `json:"msg"`
+}
+
+// Ring retains the last N log lines in memory (NewRing) or an anydb bucket (NewRingDB),
+// and is an http.Handler to view them, eg: server.Handle("/admin/logs", ring) behind your auth middleware.
+//
+// Use as a Multi Destination (or io.MultiWriter) next to the real log output.
+type Ring struct {
+	n int
+	p journalwriter.Priority
+
+	mu      sync.Mutex
+	entries []Entry // memory: circular, next is the oldest once full
+	next    int
+
+	db     *bbolt.DB
+	bucket string
+}
+
+// NewRing keeping n entries in memory, lines without a detected level are INFO
+func NewRing(n int) *Ring {
+	if n < 1 {
+		n = 1
+	}
+	return &Ring{n: n, p: journalwriter.PriInfo, entries: make([]Entry, 0, n)}
+}
+
+// NewRingDB keeping n entries in db's bucket (created if needed), survives restarts
+func NewRingDB(db *bbolt.DB, bucket string, n int) (*Ring, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := NewRing(n)
+	r.entries = nil
+	r.db, r.bucket = db, bucket
+	return r, nil
+}
+
+// Write one entry
+func (r *Ring) Write(b []byte) (int, error) {
+	p := linePriority(b, r.p)
+	e := Entry{Time: time.Now(), Priority: p, Level: p.String(), Message: string(bytes.TrimRight(b, "\n"))}
+	if r.db != nil {
+		if err := r.store(e); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) < r.n {
+		r.entries = append(r.entries, e)
+	} else {
+		r.entries[r.next] = e
+		r.next = (r.next + 1) % r.n
+	}
+	return len(b), nil
+}
+
+// keys are big endian sequence numbers, so the bucket is ordered
+func (r *Ring) store(e Entry) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		bu := tx.Bucket([]byte(r.bucket))
+		if bu == nil {
+			return bbolt.ErrBucketNotFound
+		}
+		seq, err := bu.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := anydb.StoreDB_Tx(tx, r.bucket, binary.BigEndian.AppendUint64(nil, seq), e); err != nil {
+			return err
+		}
+		if seq <= uint64(r.n) {
+			return nil
+		}
+		c := bu.Cursor() // delete the oldest beyond n
+		oldest := binary.BigEndian.AppendUint64(nil, seq-uint64(r.n))
+		for k, _ := c.First(); k != nil && bytes.Compare(k, oldest) <= 0; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Entries oldest first, with priority at most max (PriDebug for all) and containing substr (case insensitive).
+// A NewRingDB bucket is read with anydb.ForEach (nested buckets are ignored).
+func (r *Ring) Entries(max journalwriter.Priority, substr string) ([]Entry, error) {
+	var all []Entry
+	if r.db != nil {
+		err := anydb.ForEach(r.db, r.bucket, func(_ []byte, e Entry) error {
+			all = append(all, e)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		r.mu.Lock()
+		all = append(append(all, r.entries[r.next:]...), r.entries[:r.next]...)
+		r.mu.Unlock()
+	}
+	substr = strings.ToLower(substr)
+	out := all[:0]
+	for _, e := range all {
+		if e.Priority <= max && strings.Contains(strings.ToLower(e.Message), substr) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// ServeHTTP lists entries as json, newest last. Query: level=warn (and more severe), q=substring, n=limit (newest n)
+func (r *Ring) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	max := journalwriter.PriDebug
+	if lvl := query.Get("level"); lvl != "" {
+		p, ok := journalwriter.ParsePriority([]byte("level=" + lvl))
+		if !ok {
+			httpserver.ServeJson(w, http.StatusBadRequest, map[string]any{"code": http.StatusBadRequest, "error": "unknown level"})
+			return
+		}
+		max = p
+	}
+	entries, err := r.Entries(max, query.Get("q"))
+	if err != nil {
+		httpserver.ServeError(w, err)
+		return
+	}
+	if n, err := strconv.Atoi(query.Get("n")); err == nil && n >= 0 && n < len(entries) {
+		entries = entries[len(entries)-n:]
+	}
+	httpserver.ServeJson(w, http.StatusOK, entries)
+}
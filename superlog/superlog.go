@@ -10,25 +10,35 @@ import (
 	"github.com/aerth/mostly/journalwriter"
 )
 
-// New returns a non-nil io.Writer. if p is zero, uses INFO priority. if err is not nil, os.Stderr is returned with the error.
+// New returns a non-nil io.Writer for one output, see NewWithOptions for several outputs, formats and levels.
+//
+// if p is zero, uses INFO priority. if err is not nil, Stderr() is returned with the error.
+//
+// remotesyslog is "host:514" (udp), or with a scheme: "udp://", "tcp://" or "tls://".
+// Syslog uses the LOG_DAEMON facility and RFC 3164, for others see DialSyslog or NewWithOptions.
+//
+// Without syslog or journald, writes to stderr (colored if a terminal, see Stderr). For a rotating file, see OpenFile.
 func New(p journalwriter.Priority, usesyslog bool, usejournald bool, remotesyslog string) (io.Writer, error) {
 	switch {
-	case usesyslog || remotesyslog != "":
-		netw := ""
-		if remotesyslog != "" {
-			netw = "udp"
+	case remotesyslog != "":
+		netw, addr := parseRemoteSyslog(remotesyslog)
+		w, err := DialSyslog(SyslogOptions{Network: netw, Addr: addr, Priority: p})
+		if err != nil {
+			return Stderr(), err
 		}
-		syslogw, err := syslog.Dial(netw, remotesyslog, syslog.LOG_DEBUG|syslog.LOG_DAEMON, filepath.Base(os.Args[0]))
+		return w, nil
+	case usesyslog:
+		syslogw, err := syslog.Dial("", "", syslog.LOG_DEBUG|syslog.LOG_DAEMON, filepath.Base(os.Args[0]))
 		if syslogw == nil {
-			return os.Stderr, err
+			return Stderr(), err
 		}
 		return syslogw, err
 	case usejournald:
 		if !journalwriter.Enabled() {
-			return os.Stderr, fmt.Errorf("journal not enabled")
+			return Stderr(), fmt.Errorf("journal not enabled")
 		}
 		return journalwriter.JournalWriter{Priority: p}, nil
 	default:
-		return os.Stderr, nil
+		return Stderr(), nil
 	}
 }
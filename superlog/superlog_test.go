@@ -0,0 +1,463 @@
+package superlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aerth/mostly/httpserver/httpctx"
+	"github.com/aerth/mostly/journalwriter"
+	"go.etcd.io/bbolt"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	r, err := OpenFile(FileOptions{Path: path, MaxSize: 10, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	current, _ := os.ReadFile(path)
+	if string(current) != "fourth\n" {
+		t.Fatalf("current: %q", current)
+	}
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 2 || !strings.HasSuffix(backups[0], ".gz") {
+		t.Fatalf("backups: %v", backups)
+	}
+}
+
+func TestRotatingFileAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenFile(FileOptions{Path: path, MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.Write([]byte("new\n"))
+	r.Write([]byte("newer\n"))
+	if current, _ := os.ReadFile(path); string(current) != "new\nnewer\n" {
+		t.Fatalf("not rotated by mtime: %q", current)
+	}
+}
+
+func TestRotatingFilePruneError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.MkdirAll(path+".0/busy", 0o755); err != nil { // sorts first, can't be removed
+		t.Fatal(err)
+	}
+	var errs []error
+	r, err := OpenFile(FileOptions{Path: path, MaxSize: 5, MaxBackups: 1, OnError: func(err error) { errs = append(errs, err) }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.Write([]byte("first\n"))
+	if n, err := r.Write([]byte("second\n")); n != 7 || err != nil {
+		t.Fatalf("write: %d %v", n, err)
+	}
+	if current, _ := os.ReadFile(path); string(current) != "second\n" {
+		t.Fatalf("line lost: %q", current)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("errors: %v", errs)
+	}
+}
+
+func TestMulti(t *testing.T) {
+	var all, errs strings.Builder
+	m := NewMulti(0, Destination{Writer: &all}, Destination{Writer: &errs, MaxPriority: journalwriter.PriWarning})
+	m.Write([]byte("INFO started\n"))
+	m.Write([]byte("[ERROR] disk full\n"))
+	m.Write([]byte("no level\n"))
+	if all.String() != "INFO started\n[ERROR] disk full\nno level\n" || errs.String() != "[ERROR] disk full\n" {
+		t.Fatalf("all=%q errs=%q", all.String(), errs.String())
+	}
+}
+
+type lockedBuffer struct {
+	mu     sync.Mutex
+	b      strings.Builder
+	writes int
+}
+
+func (l *lockedBuffer) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writes++
+	return l.b.Write(p)
+}
+
+func TestAsync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var out lockedBuffer
+	a := NewAsync(ctx, &out, AsyncOptions{BatchSize: 1 << 20, FlushInterval: time.Hour})
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(a, "line %d\n", i)
+	}
+	cancel()
+	a.Wait()
+	fmt.Fprintf(a, "after\n")
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	if got := strings.Count(out.b.String(), "\n"); got != 101 || !strings.HasSuffix(out.b.String(), "line 99\nafter\n") {
+		t.Fatalf("lost entries: %d\n%s", got, out.b.String())
+	}
+	if out.writes > 3 {
+		t.Fatalf("not batched: %d writes", out.writes)
+	}
+}
+
+type slowWriter struct{ lockedBuffer }
+
+func (s *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	return s.lockedBuffer.Write(p)
+}
+
+func TestAsyncWriteAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var out slowWriter
+	a := NewAsync(ctx, &out, AsyncOptions{})
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(a, "line %d\n", i)
+	}
+	cancel()
+	fmt.Fprintf(a, "after\n") // not before queued lines
+	a.Wait()
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	if got := out.b.String(); strings.Count(got, "\n") != 21 || !strings.HasSuffix(got, "line 19\nafter\n") {
+		t.Fatalf("out of order:\n%s", got)
+	}
+	done, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	var direct lockedBuffer
+	fmt.Fprintf(NewAsync(done, &direct, AsyncOptions{}), "x\n") // no panic, no block
+	if direct.b.String() != "x\n" {
+		t.Fatalf("done ctx: %q", direct.b.String())
+	}
+}
+
+func TestLogfmt(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := string(AppendLogfmt(nil, ts, journalwriter.PriWarning, []byte("WRN disk \"x\" full\n"))); got != `time=2024-01-02T03:04:05Z level=warn msg="WRN disk \"x\" full"`+"\n" {
+		t.Fatalf("got %s", got)
+	}
+	if got := string(AppendLogfmt(nil, ts, journalwriter.PriInfo, []byte("level=info msg=ok"))); got != "level=info msg=ok\n" {
+		t.Fatalf("passthrough: %s", got)
+	}
+	var raw, lf strings.Builder
+	NewMulti(0, Destination{Writer: &raw}, Destination{Writer: &lf, Format: FormatLogfmt}).Write([]byte("[ERROR] boom\n"))
+	if raw.String() != "[ERROR] boom\n" || !strings.Contains(lf.String(), ` level=error msg="[ERROR] boom"`) {
+		t.Fatalf("raw=%q logfmt=%q", raw.String(), lf.String())
+	}
+	var w strings.Builder
+	Logfmt(&w, 0).Write([]byte("hello"))
+	if !strings.HasSuffix(w.String(), " level=info msg=hello\n") {
+		t.Fatalf("Logfmt: %q", w.String())
+	}
+}
+
+func TestSyslogTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := make(chan string, 1)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		b, _ := io.ReadAll(c)
+		got <- string(b)
+	}()
+	w, err := DialSyslog(SyslogOptions{Network: "tcp", Addr: ln.Addr().String(), RFC5424: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("WRN disk full\n"))
+	w.Close()
+	msg := <-got
+	n, rest, _ := strings.Cut(msg, " ")
+	if n != strconv.Itoa(len(rest)) || !strings.HasPrefix(rest, "<28>1 ") || !strings.HasSuffix(rest, " - - WRN disk full") {
+		t.Fatalf("got %q", msg)
+	}
+}
+
+func TestHandleLevelSignal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	level := journalwriter.NewPriorityVar(journalwriter.PriInfo)
+	var buf, msgs lockedBuffer
+	w := NewLevelFilter(NewMulti(0, Destination{Writer: &buf}), 0, level)
+	sc := HandleLevelSignal(ctx, level, &msgs, syscall.SIGUSR1)
+	w.Write([]byte("DEBUG hidden\n"))
+	for _, want := range []journalwriter.Priority{journalwriter.PriDebug, journalwriter.PriInfo} {
+		sc.Ch() <- syscall.SIGUSR1
+		deadline := time.Now().Add(time.Second)
+		for level.Priority() != want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if level.Priority() != want {
+			t.Fatalf("level %s, want %s", level, want)
+		}
+		w.Write([]byte("DEBUG shown when debug\n"))
+	}
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+	msgs.mu.Lock()
+	defer msgs.mu.Unlock()
+	if buf.b.String() != "DEBUG shown when debug\n" {
+		t.Fatalf("got %q", buf.b.String())
+	}
+	deadline := time.Now().Add(time.Second)
+	for msgs.writes < 2 && time.Now().Before(deadline) {
+		msgs.mu.Unlock()
+		time.Sleep(time.Millisecond)
+		msgs.mu.Lock()
+	}
+	if !strings.Contains(msgs.b.String(), "log level is now debug\n") {
+		t.Fatalf("level change not logged: %q", msgs.b.String())
+	}
+	done, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	HandleLevelSignal(done, level, &msgs, syscall.SIGUSR1) // no panic
+}
+
+func TestRepeatLimiter(t *testing.T) {
+	var buf strings.Builder
+	r := NewRepeatLimiter(&buf, RepeatOptions{Sample: 4})
+	for i := 0; i < 10; i++ {
+		r.Write([]byte("ERROR retrying\n"))
+	}
+	r.Write([]byte("ok\n"))
+	want := "ERROR retrying\nERROR retrying\nERROR retrying\nlast message repeated 7 times\nok\n"
+	if buf.String() != want {
+		t.Fatalf("got %q", buf.String())
+	}
+}
+
+func TestRepeatLimiterLogger(t *testing.T) {
+	for _, flags := range []int{log.LstdFlags, log.LstdFlags | log.Lmicroseconds | log.Lmsgprefix, log.Ltime | log.Lmicroseconds} {
+		var buf strings.Builder
+		r := NewRepeatLimiter(&buf, RepeatOptions{Prefix: "app: "})
+		l := log.New(r, "app: ", flags)
+		for i := 0; i < 5; i++ {
+			l.Println("ERROR retrying")
+			time.Sleep(time.Millisecond) // new microseconds each line
+		}
+		l.Println("ok")
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != 3 || !strings.HasSuffix(lines[0], "ERROR retrying") ||
+			lines[1] != "last message repeated 4 times" || !strings.HasSuffix(lines[2], "ok") {
+			t.Fatalf("flags %d: got %q", flags, buf.String())
+		}
+	}
+}
+
+func TestGELFChunked(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	g, err := DialGELF(GELFOptions{Addr: pc.LocalAddr().String(), ChunkSize: 100, Host: "h", Extra: map[string]any{"env": "test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	long := strings.Repeat("x", 150)
+	g.Write([]byte("ERROR first line\n" + long + "\n"))
+	var msg []byte
+	buf := make([]byte, 2048)
+	for {
+		pc.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf[0] != 0x1e || buf[1] != 0x0f || n > 100 {
+			t.Fatalf("bad chunk: %d bytes", n)
+		}
+		msg = append(msg, buf[12:n]...)
+		if buf[10] == buf[11]-1 {
+			break
+		}
+	}
+	var m map[string]any
+	if err := json.Unmarshal(msg, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["short_message"] != "ERROR first line" || m["level"] != 3.0 || m["_env"] != "test" || m["host"] != "h" || !strings.HasSuffix(m["full_message"].(string), long) {
+		t.Fatalf("got %v", m)
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	var buf strings.Builder
+	h := RequestLoggerMiddleware(slog.NewTextHandler(&buf, nil))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpctx.GetLogger(r.Context()).Info("handled")
+	}))
+	r := httptest.NewRequest("GET", "/users?id=1", nil)
+	r = r.WithContext(context.WithValue(r.Context(), httpctx.KUUID, 1234))
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if !strings.Contains(buf.String(), "msg=handled request_id=1234 method=GET path=/users client_ip=192.0.2.1") {
+		t.Fatalf("got %s", buf.String())
+	}
+	if httpctx.GetLogger(context.Background()) != slog.Default() {
+		t.Fatal("default logger")
+	}
+}
+
+func TestRing(t *testing.T) {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "logs.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	dbring, err := NewRingDB(db, "logs", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []*Ring{NewRing(3), dbring} {
+		for _, line := range []string{"INFO one\n", "ERROR two\n", "INFO three\n", "WARN four\n", "DEBUG five\n"} {
+			r.Write([]byte(line))
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/logs?level=warn", nil))
+		var got []Entry
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Message != "WARN four" || got[0].Level != "warn" {
+			t.Fatalf("level filter: %+v", got)
+		}
+		all, _ := r.Entries(journalwriter.PriDebug, "")
+		if len(all) != 3 || all[0].Message != "INFO three" || all[2].Message != "DEBUG five" {
+			t.Fatalf("ring: %+v", all)
+		}
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.Bucket([]byte("logs")).CreateBucket([]byte("nested"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all, err := dbring.Entries(journalwriter.PriDebug, ""); err != nil || len(all) != 3 {
+		t.Fatalf("nested bucket: %d %v", len(all), err)
+	}
+}
+
+func TestConsole(t *testing.T) {
+	var buf strings.Builder
+	c := NewConsole(&buf, ConsoleOptions{Color: true})
+	c.Write([]byte("[ERROR] boom\n"))
+	c.Write([]byte("plain"))
+	if buf.String() != "\033[31m[ERROR] boom\033[0m\nplain\n" {
+		t.Fatalf("got %q", buf.String())
+	}
+	buf.Reset()
+	NewConsole(&buf, ConsoleOptions{Time: true}).Write([]byte("x\n"))
+	if len(buf.String()) != len("15:04:05.000 x\n") {
+		t.Fatalf("time: %q", buf.String())
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	var extra strings.Builder
+	fallback := new(strings.Builder)
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewWithOptions(Options{
+		File:     &FileOptions{Path: path},
+		FileSink: SinkOptions{Format: FormatLogfmt, MaxPriority: journalwriter.PriWarning},
+		GELF:     &GELFOptions{Network: "bogus"},
+		Extra:    []Destination{{Writer: &extra}},
+		Repeat:   &RepeatOptions{},
+		Fallback: fallback,
+	})
+	if err == nil || !strings.Contains(err.Error(), "gelf") {
+		t.Fatalf("expected gelf error, got %v", err)
+	}
+	w.Write([]byte("INFO hello\n"))
+	w.Write([]byte("ERROR boom\n"))
+	w.Write([]byte("ERROR boom\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("ERROR closed\n")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("file not closed: %v", err)
+	}
+	logged, _ := os.ReadFile(path)
+	if !strings.Contains(string(logged), `level=error msg="ERROR boom"`) || strings.Contains(string(logged), "hello") {
+		t.Fatalf("file: %s", logged)
+	}
+	if !strings.HasPrefix(extra.String(), "INFO hello\nERROR boom\nlast message repeated 1 times\n") || !strings.HasPrefix(fallback.String(), extra.String()) {
+		t.Fatalf("extra=%q fallback=%q", extra.String(), fallback.String())
+	}
+	extra.Reset()
+	level := journalwriter.NewPriorityVar(journalwriter.PriWarning)
+	w, err = NewWithOptions(Options{Extra: []Destination{{Writer: &extra}}, MinPriority: level})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte("INFO hidden\n"))
+	level.Set(journalwriter.PriInfo)
+	w.Write([]byte("INFO shown\n"))
+	if extra.String() != "INFO shown\n" {
+		t.Fatalf("MinPriority: %q", extra.String())
+	}
+}
+
+func TestVerbosityPriority(t *testing.T) {
+	for n, want := range []journalwriter.Priority{journalwriter.PriNotice, journalwriter.PriInfo, journalwriter.PriDebug, journalwriter.PriDebug} {
+		if got := VerbosityPriority(journalwriter.PriNotice, n); got != want {
+			t.Fatalf("-v x%d: got %s want %s", n, got, want)
+		}
+	}
+}
+
+func TestUseConsoleFallback(t *testing.T) {
+	if journalwriter.FallbackWriter != io.Writer(os.Stderr) {
+		t.Fatal("importing superlog changed journalwriter.FallbackWriter")
+	}
+	defer func(w io.Writer) { journalwriter.FallbackWriter = w }(journalwriter.FallbackWriter)
+	var buf strings.Builder
+	journalwriter.FallbackWriter = &buf
+	UseConsoleFallback()
+	if journalwriter.FallbackWriter != io.Writer(&buf) {
+		t.Fatal("replaced a custom FallbackWriter")
+	}
+}
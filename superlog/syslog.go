@@ -0,0 +1,160 @@
+package superlog
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"log/syslog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerth/mostly/journalwriter"
+)
+
+// SyslogOptions for DialSyslog
+type SyslogOptions struct {
+	Network        string                 // "udp", "tcp" or "tcp+tls"
+	Addr           string                 // host:port
+	TLSConfig      *tls.Config            // for tcp+tls, nil uses system roots
+	Facility       syslog.Priority        // default LOG_DAEMON
+	Tag            string                 // app name, default program name
+	Hostname       string                 // default os.Hostname
+	RFC5424        bool                   // RFC 5424 format (default is RFC 3164, like log/syslog)
+	StructuredData string                 // RFC 5424 SD, eg: [origin@32473 env="prod"], default "-"
+	Priority       journalwriter.Priority // severity of lines without a detected level (default INFO)
+	Timeout        time.Duration          // dial timeout, default 10s
+}
+
+// SyslogWriter sends each write as one syslog message, with the severity detected by journalwriter.ParsePriority.
+//
+// Stream transports use octet counting framing (RFC 6587) for RFC 5424, newline framing otherwise. Reconnects once on error.
+type SyslogWriter struct {
+	opts SyslogOptions
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// DialSyslog connects to a remote syslog server
+func DialSyslog(opts SyslogOptions) (*SyslogWriter, error) {
+	switch opts.Network {
+	case "udp", "tcp", "tcp+tls":
+	default:
+		return nil, fmt.Errorf("superlog: unsupported syslog network: %q", opts.Network)
+	}
+	if opts.Tag == "" {
+		opts.Tag = filepath.Base(os.Args[0])
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	if opts.Priority == 0 {
+		opts.Priority = journalwriter.PriInfo
+	}
+	if opts.Facility == 0 {
+		opts.Facility = syslog.LOG_DAEMON
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	w := &SyslogWriter{opts: opts}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *SyslogWriter) connect() error {
+	var (
+		conn net.Conn
+		err  error
+	)
+	if w.opts.Network == "tcp+tls" {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: w.opts.Timeout}, "tcp", w.opts.Addr, w.opts.TLSConfig)
+	} else {
+		conn, err = net.DialTimeout(w.opts.Network, w.opts.Addr, w.opts.Timeout)
+	}
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+// Write b as one message
+func (w *SyslogWriter) Write(b []byte) (int, error) {
+	p := linePriority(b, w.opts.Priority)
+	msg := w.format(time.Now(), p, bytes.TrimRight(b, "\n"))
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for try := 0; ; try++ {
+		if w.conn == nil {
+			if err := w.connect(); err != nil {
+				return 0, err
+			}
+		}
+		_, err := w.conn.Write(msg)
+		if err == nil {
+			return len(b), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+		if try > 0 {
+			return 0, err
+		}
+	}
+}
+
+// Close the connection
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+func (w *SyslogWriter) format(t time.Time, p journalwriter.Priority, msg []byte) []byte {
+	pri := int(w.opts.Facility) | int(p)
+	var out []byte
+	if w.opts.RFC5424 {
+		sd := w.opts.StructuredData
+		if sd == "" {
+			sd = "-"
+		}
+		out = fmt.Appendf(nil, "<%d>1 %s %s %s %d - %s %s", pri, t.Format(time.RFC3339Nano), nilvalue(w.opts.Hostname), nilvalue(w.opts.Tag), os.Getpid(), sd, msg)
+		if w.opts.Network != "udp" {
+			return append(fmt.Appendf(nil, "%d ", len(out)), out...)
+		}
+		return out
+	}
+	out = fmt.Appendf(nil, "<%d>%s %s %s[%d]: %s", pri, t.Format(time.Stamp), w.opts.Hostname, w.opts.Tag, os.Getpid(), msg)
+	if w.opts.Network != "udp" {
+		out = append(out, '\n')
+	}
+	return out
+}
+
+// nilvalue is "-" for empty RFC 5424 header fields (no spaces allowed)
+func nilvalue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, " ", "_")
+}
+
+// parseRemoteSyslog: "host:514" (udp), "udp://host:514", "tcp://host:514", "tls://host:6514"
+func parseRemoteSyslog(remote string) (network, addr string) {
+	for prefix, netw := range map[string]string{"udp://": "udp", "tcp://": "tcp", "tls://": "tcp+tls", "tcp+tls://": "tcp+tls"} {
+		if a, ok := strings.CutPrefix(remote, prefix); ok {
+			return netw, a
+		}
+	}
+	return "udp", remote
+}
@@ -0,0 +1,130 @@
+package unixtimestamp
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// BinaryFormat for MarshalBinaryWith and UnmarshalBinaryWith, without touching the global Endian
+type BinaryFormat struct {
+	Order    binary.ByteOrder // nil uses Endian
+	Size     int              // 8 (default) or 4 (uint32, seconds until 2106)
+	Varint   bool             // signed varint (1-10 bytes), ignores Order and Size
+	Sortable bool             // flip the sign bit (Size 8), so big endian bytes of pre-1970 times sort first
+}
+
+var (
+	// KeyFormat is 8 big endian bytes, sorts correctly as bbolt/anydb keys (including pre-1970)
+	KeyFormat = BinaryFormat{Order: binary.BigEndian, Size: 8, Sortable: true}
+	// Key32Format is 4 big endian bytes, sorts correctly as keys (seconds: until 2106)
+	Key32Format = BinaryFormat{Order: binary.BigEndian, Size: 4}
+	// CompactFormat is a varint, small for storage but does not sort
+	CompactFormat = BinaryFormat{Varint: true}
+)
+
+// MarshalBinaryWith format (FuncFrom units), see KeyFormat and CompactFormat
+func (ut UnixTimestamp) MarshalBinaryWith(f BinaryFormat) ([]byte, error) {
+	return marshalBinaryWith(ut.Time, f, FuncFrom)
+}
+
+// UnmarshalBinaryWith format (FuncTo units), see MarshalBinaryWith
+func (ut *UnixTimestamp) UnmarshalBinaryWith(f BinaryFormat, dat []byte) error {
+	return unmarshalBinaryWith(&ut.Time, f, dat, FuncTo, Options{})
+}
+
+// MarshalBinaryWith format (seconds), see KeyFormat and CompactFormat
+func (ut Seconds) MarshalBinaryWith(f BinaryFormat) ([]byte, error) {
+	return marshalBinaryWith(ut.Time, f, FSeconds)
+}
+
+// UnmarshalBinaryWith format (seconds), see MarshalBinaryWith
+func (ut *Seconds) UnmarshalBinaryWith(f BinaryFormat, dat []byte) error {
+	return unmarshalBinaryWith(&ut.Time, f, dat, TSeconds, Options{})
+}
+
+// MarshalBinaryWith format (milliseconds), see KeyFormat and CompactFormat
+func (ut Millis) MarshalBinaryWith(f BinaryFormat) ([]byte, error) {
+	return marshalBinaryWith(ut.Time, f, FMilli)
+}
+
+// UnmarshalBinaryWith format (milliseconds), see MarshalBinaryWith
+func (ut *Millis) UnmarshalBinaryWith(f BinaryFormat, dat []byte) error {
+	return unmarshalBinaryWith(&ut.Time, f, dat, TMilli, Options{})
+}
+
+// MarshalBinaryWith format (microseconds), see KeyFormat and CompactFormat
+func (ut Micros) MarshalBinaryWith(f BinaryFormat) ([]byte, error) {
+	return marshalBinaryWith(ut.Time, f, FMicro)
+}
+
+// UnmarshalBinaryWith format (microseconds), see MarshalBinaryWith
+func (ut *Micros) UnmarshalBinaryWith(f BinaryFormat, dat []byte) error {
+	return unmarshalBinaryWith(&ut.Time, f, dat, TMicro, Options{})
+}
+
+// signBit flipped by BinaryFormat.Sortable
+const signBit = 1 << 63
+
+func marshalBinaryWith(t time.Time, f BinaryFormat, from func(time.Time) int64) ([]byte, error) {
+	var n int64 // 0 for zero time, negative before 1970
+	if !t.IsZero() {
+		n = from(t)
+	}
+	if f.Varint {
+		return binary.AppendVarint(nil, n), nil
+	}
+	order := f.Order
+	if order == nil {
+		order = Endian
+	}
+	switch f.Size {
+	case 0, 8:
+		u := uint64(n)
+		if f.Sortable {
+			u ^= signBit
+		}
+		buf := make([]byte, 8)
+		order.PutUint64(buf, u)
+		return buf, nil
+	case 4:
+		if n < 0 || n > math.MaxUint32 {
+			return nil, Errorf("unixtimestamp: %d does not fit in 4 bytes", n)
+		}
+		buf := make([]byte, 4)
+		order.PutUint32(buf, uint32(n))
+		return buf, nil
+	}
+	return nil, Errorf("unixtimestamp: unsupported binary size: %d", f.Size)
+}
+
+func unmarshalBinaryWith(t *time.Time, f BinaryFormat, dat []byte, to func(int64) time.Time, o Options) error {
+	if f.Varint {
+		n, l := binary.Varint(dat)
+		if l <= 0 {
+			return Errorf("unixtimestamp: bad varint")
+		}
+		return fromUnix(t, n, to, o)
+	}
+	order := f.Order
+	if order == nil {
+		order = Endian
+	}
+	switch f.Size {
+	case 0, 8:
+		if len(dat) < 8 {
+			return Errorf("unixtimestamp: binary too short: %d bytes", len(dat))
+		}
+		u := order.Uint64(dat)
+		if f.Sortable {
+			u ^= signBit
+		}
+		return fromUnix(t, int64(u), to, o)
+	case 4:
+		if len(dat) < 4 {
+			return Errorf("unixtimestamp: binary too short: %d bytes", len(dat))
+		}
+		return fromUnix(t, int64(order.Uint32(dat)), to, o)
+	}
+	return Errorf("unixtimestamp: unsupported binary size: %d", f.Size)
+}
@@ -0,0 +1,98 @@
+package unixtimestamp
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// bson support without importing a bson package.
+// Signatures match go.mongodb.org/mongo-driver/v2/bson ValueMarshaler and ValueUnmarshaler.
+
+// bson element types
+const (
+	bsonDouble   byte = 0x01
+	bsonString   byte = 0x02
+	bsonDatetime byte = 0x09
+	bsonNull     byte = 0x0A
+	bsonInt32    byte = 0x10
+	bsonInt64    byte = 0x12
+)
+
+// MarshalBSONValue as int64 (or datetime, see Options.BSONDatetime)
+func (ut UnixTimestamp) MarshalBSONValue() (byte, []byte, error) {
+	return marshalBSONValue(ut.Time, FuncFrom, Options{})
+}
+
+// UnmarshalBSONValue accepts int32, int64, double, datetime, string (numeric or RFC3339) or null
+func (ut *UnixTimestamp) UnmarshalBSONValue(typ byte, data []byte) error {
+	return unmarshalBSONValue(&ut.Time, typ, data, FuncTo, Options{})
+}
+
+// MarshalBSONValue as int64 (or datetime, see Options.BSONDatetime)
+func (ut Seconds) MarshalBSONValue() (byte, []byte, error) {
+	return marshalBSONValue(ut.Time, FSeconds, Options{})
+}
+
+// UnmarshalBSONValue accepts int32, int64, double, datetime, string (numeric or RFC3339) or null
+func (ut *Seconds) UnmarshalBSONValue(typ byte, data []byte) error {
+	return unmarshalBSONValue(&ut.Time, typ, data, TSeconds, Options{})
+}
+
+// MarshalBSONValue as int64 (or datetime, see Options.BSONDatetime)
+func (ut Millis) MarshalBSONValue() (byte, []byte, error) {
+	return marshalBSONValue(ut.Time, FMilli, Options{})
+}
+
+// UnmarshalBSONValue accepts int32, int64, double, datetime, string (numeric or RFC3339) or null
+func (ut *Millis) UnmarshalBSONValue(typ byte, data []byte) error {
+	return unmarshalBSONValue(&ut.Time, typ, data, TMilli, Options{})
+}
+
+// MarshalBSONValue as int64 (or datetime, see Options.BSONDatetime)
+func (ut Micros) MarshalBSONValue() (byte, []byte, error) {
+	return marshalBSONValue(ut.Time, FMicro, Options{})
+}
+
+// UnmarshalBSONValue accepts int32, int64, double, datetime, string (numeric or RFC3339) or null
+func (ut *Micros) UnmarshalBSONValue(typ byte, data []byte) error {
+	return unmarshalBSONValue(&ut.Time, typ, data, TMicro, Options{})
+}
+
+func marshalBSONValue(t time.Time, from func(time.Time) int64, o Options) (byte, []byte, error) {
+	if o.BSONDatetime {
+		if !t.After(zerotime) {
+			return bsonNull, nil, nil
+		}
+		return bsonDatetime, binary.LittleEndian.AppendUint64(nil, uint64(t.UnixMilli())), nil
+	}
+	return bsonInt64, binary.LittleEndian.AppendUint64(nil, uint64(unixInt(t, from))), nil
+}
+
+func unmarshalBSONValue(t *time.Time, typ byte, data []byte, to func(int64) time.Time, o Options) error {
+	need := map[byte]int{bsonDouble: 8, bsonDatetime: 8, bsonInt64: 8, bsonInt32: 4, bsonString: 5}[typ]
+	if len(data) < need {
+		return Errorf("unixtimestamp: bson value too short: %d bytes", len(data))
+	}
+	switch typ {
+	case bsonNull:
+		*t = time.Time{}
+	case bsonDatetime:
+		*t = time.UnixMilli(int64(binary.LittleEndian.Uint64(data)))
+	case bsonInt64:
+		return fromAny(t, int64(binary.LittleEndian.Uint64(data)), to, o)
+	case bsonInt32:
+		return fromAny(t, int64(int32(binary.LittleEndian.Uint32(data))), to, o)
+	case bsonDouble:
+		return fromAny(t, math.Float64frombits(binary.LittleEndian.Uint64(data)), to, o)
+	case bsonString:
+		l := int(int32(binary.LittleEndian.Uint32(data)))
+		if l < 1 || len(data) < 4+l {
+			return Errorf("unixtimestamp: bad bson string")
+		}
+		return fromAny(t, string(data[4:4+l-1]), to, o) // without trailing NUL
+	default:
+		return Errorf("unixtimestamp: unsupported bson type: 0x%02x", typ)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package unixtimestamp
+
+import "time"
+
+// Calendar helpers, all nil-safe (nil is zero time) and returning a new *UnixTimestamp.
+// Names avoid shadowing the embedded time.Time methods (Truncate, Before, ...).
+
+func (ut *UnixTimestamp) time() time.Time {
+	if ut == nil {
+		return time.Time{}
+	}
+	return ut.Time
+}
+
+// StartOfDay is midnight of ut's day in loc (nil loc is ut's own location)
+func (ut *UnixTimestamp) StartOfDay(loc *time.Location) *UnixTimestamp {
+	t := ut.time()
+	if loc != nil {
+		t = t.In(loc)
+	}
+	y, m, d := t.Date()
+	return New(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+}
+
+// EndOfDay is the last nanosecond of ut's day in loc (nil loc is ut's own location)
+func (ut *UnixTimestamp) EndOfDay(loc *time.Location) *UnixTimestamp {
+	start := ut.StartOfDay(loc).Time
+	y, m, d := start.Date()
+	return New(time.Date(y, m, d+1, 0, 0, 0, 0, start.Location()).Add(-time.Nanosecond))
+}
+
+// TruncateTo rounds down to a multiple of d (see time.Time.Truncate)
+func (ut *UnixTimestamp) TruncateTo(d time.Duration) *UnixTimestamp {
+	return New(ut.time().Truncate(d))
+}
+
+// AddDays by calendar (DST safe), negative to go back
+func (ut *UnixTimestamp) AddDays(days int) *UnixTimestamp {
+	return New(ut.time().AddDate(0, 0, days))
+}
+
+// AddMonths by calendar, overflow normalizes like time.AddDate (Jan 31 + 1 month is Mar 2 or 3)
+func (ut *UnixTimestamp) AddMonths(months int) *UnixTimestamp {
+	return New(ut.time().AddDate(0, months, 0))
+}
+
+// IsBefore u (nil is zero time)
+func (ut *UnixTimestamp) IsBefore(u *UnixTimestamp) bool {
+	return ut.time().Before(u.time())
+}
+
+// IsAfter u (nil is zero time)
+func (ut *UnixTimestamp) IsAfter(u *UnixTimestamp) bool {
+	return ut.time().After(u.time())
+}
+
+// IsEqual u (nil is zero time, so nil equals a zero UnixTimestamp)
+func (ut *UnixTimestamp) IsEqual(u *UnixTimestamp) bool {
+	return ut.time().Equal(u.time())
+}
+
+// CompareTo returns -1, 0 or +1 (nil is zero time)
+func (ut *UnixTimestamp) CompareTo(u *UnixTimestamp) int {
+	return ut.time().Compare(u.time())
+}
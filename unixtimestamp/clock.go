@@ -0,0 +1,59 @@
+package unixtimestamp
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is used by Now(), see DefaultClock and ManualClock
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock uses time.Now
+var SystemClock Clock = systemClock{}
+
+// DefaultClock is used by Now(), replace in tests with a *ManualClock (and restore with t.Cleanup)
+var DefaultClock = SystemClock
+
+// NowFrom returns a new UnixTimestamp for the clock's current time (UTC)
+func NowFrom(c Clock) *UnixTimestamp {
+	return New(c.Now().UTC()) // nil tz and remove monotonic clock
+}
+
+// ManualClock only moves when told to, for deterministic tests
+type ManualClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// NewManualClock frozen at t
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{t: t}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+// Set the current time
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.t = t
+	c.mu.Unlock()
+}
+
+// Add d to the current time
+func (c *ManualClock) Add(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
@@ -0,0 +1,55 @@
+package unixtimestamp
+
+import (
+	"flag"
+	"strconv"
+	"time"
+)
+
+var _ flag.Getter = (*UnixTimestamp)(nil) // compile-time interface check
+
+// ParseFlag parses unix seconds ("1700000000"), RFC3339 ("2023-11-14T22:13:20Z") or a date ("2023-11-14", UTC).
+//
+// Empty string is zero time.
+func ParseFlag(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if unix == 0 {
+			return time.Time{}, nil
+		}
+		return TSeconds(unix), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return t, Errorf("unixtimestamp: expected unix seconds, RFC3339 or date: %q", s)
+	}
+	return t, nil
+}
+
+// Set implements flag.Value, see ParseFlag
+func (ut *UnixTimestamp) Set(s string) error {
+	t, err := ParseFlag(s)
+	if err != nil {
+		return err
+	}
+	ut.Time = t
+	return nil
+}
+
+// Get implements flag.Getter
+func (ut *UnixTimestamp) Get() any {
+	return ut
+}
+
+// FlagVar defines a timestamp flag (eg: --since), see ParseFlag
+//
+//	var since unixtimestamp.UnixTimestamp
+//	unixtimestamp.FlagVar(&since, "since", "only show entries after (unix seconds or RFC3339)")
+func FlagVar(p *UnixTimestamp, name string, usage string) {
+	flag.CommandLine.Var(p, name, usage)
+}
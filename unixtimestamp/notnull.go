@@ -0,0 +1,71 @@
+package unixtimestamp
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// epoch is stored by UnixTimestampNotNull.Value for zero time (and scanned back as zero time), see Options.NotNullZero
+var epoch = time.Unix(0, 0).UTC()
+
+// ErrNull when scanning or unmarshaling NULL/null into UnixTimestampNotNull (or With, see Options.NotNull)
+var ErrNull = Errorf("unixtimestamp: null value for not null timestamp")
+
+// NewNotNull existing time.Time
+func NewNotNull(t time.Time) *UnixTimestampNotNull {
+	return &UnixTimestampNotNull{UnixTimestamp{Time: t}}
+}
+
+// Value is never nil. Zero time (or nil pointer) is the unix epoch.
+func (u *UnixTimestampNotNull) Value() (driver.Value, error) {
+	var t time.Time
+	if u != nil {
+		t = u.Time
+	}
+	return notNullValue(t, FuncFrom, Options{})
+}
+
+// Scan returns ErrNull for NULL. The unix epoch is scanned as zero time.
+func (u *UnixTimestampNotNull) Scan(v interface{}) error {
+	return scanNotNull(&u.Time, v, FuncTo, Options{})
+}
+
+// MarshalJSON is always a number (0 for zero time)
+func (u UnixTimestampNotNull) MarshalJSON() ([]byte, error) {
+	return marshalJSON(u.Time, FuncFrom)
+}
+
+// UnmarshalJSON returns ErrNull for null, unlike UnixTimestamp
+func (u *UnixTimestampNotNull) UnmarshalJSON(dat []byte) error {
+	return unmarshalJSONNotNull(&u.Time, dat, FuncTo, Options{})
+}
+
+func notNullValue(t time.Time, from func(time.Time) int64, o Options) (driver.Value, error) {
+	if t.IsZero() {
+		t = o.notNullZero()
+	}
+	if o.SQLInteger {
+		return from(t), nil
+	}
+	return t, nil
+}
+
+func scanNotNull(t *time.Time, v interface{}, to func(int64) time.Time, o Options) error {
+	if v == nil {
+		return ErrNull
+	}
+	if err := scan(t, v, to, o); err != nil {
+		return err
+	}
+	if t.Equal(o.notNullZero()) {
+		*t = time.Time{}
+	}
+	return nil
+}
+
+func unmarshalJSONNotNull(t *time.Time, dat []byte, to func(int64) time.Time, o Options) error {
+	if string(dat) == "null" {
+		return ErrNull
+	}
+	return unmarshalJSON(t, dat, to, o)
+}
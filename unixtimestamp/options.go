@@ -0,0 +1,125 @@
+package unixtimestamp
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Options for one timestamp type instead of process-wide settings, see With
+type Options struct {
+	Unmarshal UnmarshalOptions // UnmarshalJSON, default strict
+
+	// BSONDatetime stores as native bson datetime (milliseconds) instead of int64 in the type's unit.
+	// Either is accepted when unmarshaling.
+	BSONDatetime bool
+
+	// SQLInteger makes Value() return the unix integer instead of time.Time, for BIGINT columns.
+	// Scan accepts either.
+	SQLInteger bool
+
+	// AutoCorrectUnits guesses the right unit instead of returning UnitMismatchError (json, sql, yaml, toml, bson, binary)
+	AutoCorrectUnits bool
+
+	// NotNull is UnixTimestampNotNull behavior: Value is never nil (zero time is NotNullZero), NULL/null input is ErrNull
+	NotNull bool
+	// NotNullZero is stored for zero time and scanned back as zero time, nil is the unix epoch.
+	// Some schemas prefer a sentinel like 0001-01-01 (&time.Time{}).
+	NotNullZero *time.Time
+}
+
+// OptionSet gives the Options of With, implement it on an empty struct type:
+//
+//	type apiTime struct{}
+//
+//	func (apiTime) Options() unixtimestamp.Options {
+//		return unixtimestamp.Options{Unmarshal: unixtimestamp.FlexibleUnmarshal}
+//	}
+//
+//	var v struct {
+//		Created unixtimestamp.With[apiTime] `json:"created"`
+//	}
+type OptionSet interface {
+	Options() Options
+}
+
+// With is a UnixTimestamp (FuncTo/FuncFrom units) using the Options of O.
+// Methods not affected by Options are the UnixTimestamp ones.
+type With[O OptionSet] struct {
+	UnixTimestamp
+}
+
+// NewWith existing time.Time
+func NewWith[O OptionSet](t time.Time) *With[O] {
+	return &With[O]{UnixTimestamp{Time: t}}
+}
+
+func options[O OptionSet]() Options {
+	var o O
+	return o.Options()
+}
+
+func (o Options) notNullZero() time.Time {
+	if o.NotNullZero == nil {
+		return epoch
+	}
+	return *o.NotNullZero
+}
+
+// UnmarshalJSON with Options.Unmarshal, null is ErrNull with Options.NotNull
+func (ut *With[O]) UnmarshalJSON(dat []byte) error {
+	if o := options[O](); o.NotNull {
+		return unmarshalJSONNotNull(&ut.Time, dat, FuncTo, o)
+	}
+	return unmarshalJSON(&ut.Time, dat, FuncTo, options[O]())
+}
+
+// Scan implements sql.Scanner, see UnixTimestamp.Scan. NULL is ErrNull with Options.NotNull
+func (ut *With[O]) Scan(v interface{}) error {
+	if o := options[O](); o.NotNull {
+		return scanNotNull(&ut.Time, v, FuncTo, o)
+	}
+	return scan(&ut.Time, v, FuncTo, options[O]())
+}
+
+// UnmarshalBSONValue accepts int32, int64, double, datetime, string (numeric or RFC3339) or null
+func (ut *With[O]) UnmarshalBSONValue(typ byte, data []byte) error {
+	return unmarshalBSONValue(&ut.Time, typ, data, FuncTo, options[O]())
+}
+
+// UnmarshalYAML accepts a unix integer, a numeric string, or a yaml/RFC3339 timestamp
+func (ut *With[O]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(&ut.Time, unmarshal, FuncTo, options[O]())
+}
+
+// UnmarshalTOML accepts a unix integer, a numeric string, or a toml datetime
+func (ut *With[O]) UnmarshalTOML(v interface{}) error {
+	return fromAny(&ut.Time, v, FuncTo, options[O]())
+}
+
+// UnmarshalText accepts a unix integer or a datetime string
+func (ut *With[O]) UnmarshalText(dat []byte) error {
+	return fromAny(&ut.Time, string(dat), FuncTo, options[O]())
+}
+
+// UnmarshalBinaryWith format, see MarshalBinaryWith
+func (ut *With[O]) UnmarshalBinaryWith(f BinaryFormat, dat []byte) error {
+	return unmarshalBinaryWith(&ut.Time, f, dat, FuncTo, options[O]())
+}
+
+// MarshalBSONValue as int64, or datetime with Options.BSONDatetime
+func (ut With[O]) MarshalBSONValue() (byte, []byte, error) {
+	return marshalBSONValue(ut.Time, FuncFrom, options[O]())
+}
+
+// Value is nil if zero time (NotNullZero with Options.NotNull), the unix integer with Options.SQLInteger
+func (ut *With[O]) Value() (driver.Value, error) {
+	o := options[O]()
+	var t time.Time
+	if ut != nil {
+		t = ut.Time
+	}
+	if o.NotNull {
+		return notNullValue(t, FuncFrom, o)
+	}
+	return value(t, FuncFrom, o)
+}
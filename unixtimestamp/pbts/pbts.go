@@ -0,0 +1,41 @@
+// pbts package converts between unixtimestamp and protobuf Timestamp (separate package to keep the protobuf dependency optional)
+package pbts
+
+import (
+	"time"
+
+	"github.com/aerth/mostly/unixtimestamp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FromProto returns nil for nil or zero (epoch) Timestamp
+func FromProto(ts *timestamppb.Timestamp) *unixtimestamp.UnixTimestamp {
+	if ts == nil || (ts.GetSeconds() == 0 && ts.GetNanos() == 0) {
+		return nil
+	}
+	return unixtimestamp.New(ts.AsTime())
+}
+
+// ToProto returns nil for nil or zero time
+func ToProto(ut *unixtimestamp.UnixTimestamp) *timestamppb.Timestamp {
+	if ut == nil || ut.Time.IsZero() {
+		return nil
+	}
+	return timestamppb.New(ut.Time)
+}
+
+// FromProtoTime is FromProto for any of the unixtimestamp types, eg: pbts.FromProtoTime(ts, unixtimestamp.NewMillis)
+func FromProtoTime[T any](ts *timestamppb.Timestamp, fn func(time.Time) *T) *T {
+	if ts == nil || (ts.GetSeconds() == 0 && ts.GetNanos() == 0) {
+		return nil
+	}
+	return fn(ts.AsTime())
+}
+
+// ToProtoTime is ToProto for any time.Time (zero time is nil)
+func ToProtoTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
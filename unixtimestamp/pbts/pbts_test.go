@@ -0,0 +1,36 @@
+package pbts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aerth/mostly/unixtimestamp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, tm := range []time.Time{time.Unix(1700000000, 123456789), time.Unix(-86400, 5), time.Unix(0, 1)} {
+		ts := ToProto(unixtimestamp.New(tm))
+		if ts == nil || ts.GetSeconds() != tm.Unix() || ts.GetNanos() != int32(tm.Nanosecond()) {
+			t.Fatalf("%v: ToProto: %v", tm, ts)
+		}
+		if got := FromProto(ts); got == nil || !got.Equal(tm) {
+			t.Fatalf("%v: FromProto: %v", tm, got)
+		}
+		if got := FromProtoTime(ToProtoTime(tm), unixtimestamp.NewMillis); got == nil || !got.Equal(tm) {
+			t.Fatalf("%v: FromProtoTime: %v", tm, got)
+		}
+	}
+}
+
+func TestNilZero(t *testing.T) {
+	if ToProto(nil) != nil || ToProto(&unixtimestamp.UnixTimestamp{}) != nil || ToProtoTime(time.Time{}) != nil {
+		t.Fatal("zero time should be nil Timestamp")
+	}
+	if FromProto(nil) != nil || FromProto(&timestamppb.Timestamp{}) != nil {
+		t.Fatal("nil or epoch Timestamp should be nil")
+	}
+	if FromProtoTime(nil, unixtimestamp.NewSeconds) != nil || FromProtoTime(&timestamppb.Timestamp{}, unixtimestamp.NewSeconds) != nil {
+		t.Fatal("FromProtoTime: nil or epoch Timestamp should be nil")
+	}
+}
@@ -0,0 +1,147 @@
+package unixtimestamp
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Seconds is a time.Time that marshals to/from Unix timestamp (seconds), regardless of FuncTo/FuncFrom
+type Seconds struct {
+	time.Time
+}
+
+// Millis is a time.Time that marshals to/from Unix timestamp (milliseconds), regardless of FuncTo/FuncFrom
+type Millis struct {
+	time.Time
+}
+
+// Micros is a time.Time that marshals to/from Unix timestamp (microseconds), regardless of FuncTo/FuncFrom
+type Micros struct {
+	time.Time
+}
+
+// NewSeconds existing time.Time
+func NewSeconds(t time.Time) *Seconds {
+	return &Seconds{Time: t}
+}
+
+// NewMillis existing time.Time
+func NewMillis(t time.Time) *Millis {
+	return &Millis{Time: t}
+}
+
+// NewMicros existing time.Time
+func NewMicros(t time.Time) *Micros {
+	return &Micros{Time: t}
+}
+
+func (ut *Seconds) String() string {
+	if ut == nil || ut.Time.IsZero() {
+		return "(none)"
+	}
+	return ut.Time.String()
+}
+
+func (ut Seconds) MarshalJSON() ([]byte, error) {
+	return marshalJSON(ut.Time, FSeconds)
+}
+
+func (ut *Seconds) UnmarshalJSON(dat []byte) error {
+	return unmarshalJSON(&ut.Time, dat, TSeconds, Options{})
+}
+
+// MarshalBinary uses Endian var
+func (ut Seconds) MarshalBinary() ([]byte, error) {
+	return marshalBinary(ut.Time, FSeconds)
+}
+
+// UnmarshalBinary uses Endian var
+func (ut *Seconds) UnmarshalBinary(dat []byte) error {
+	return unmarshalBinary(&ut.Time, dat, TSeconds)
+}
+
+func (ut *Seconds) Scan(v interface{}) error {
+	return scan(&ut.Time, v, TSeconds, Options{})
+}
+
+// Value is nil if zero time
+func (ut *Seconds) Value() (driver.Value, error) {
+	if ut == nil {
+		return nil, nil
+	}
+	return value(ut.Time, FSeconds, Options{})
+}
+
+func (ut *Millis) String() string {
+	if ut == nil || ut.Time.IsZero() {
+		return "(none)"
+	}
+	return ut.Time.String()
+}
+
+func (ut Millis) MarshalJSON() ([]byte, error) {
+	return marshalJSON(ut.Time, FMilli)
+}
+
+func (ut *Millis) UnmarshalJSON(dat []byte) error {
+	return unmarshalJSON(&ut.Time, dat, TMilli, Options{})
+}
+
+// MarshalBinary uses Endian var
+func (ut Millis) MarshalBinary() ([]byte, error) {
+	return marshalBinary(ut.Time, FMilli)
+}
+
+// UnmarshalBinary uses Endian var
+func (ut *Millis) UnmarshalBinary(dat []byte) error {
+	return unmarshalBinary(&ut.Time, dat, TMilli)
+}
+
+func (ut *Millis) Scan(v interface{}) error {
+	return scan(&ut.Time, v, TMilli, Options{})
+}
+
+// Value is nil if zero time
+func (ut *Millis) Value() (driver.Value, error) {
+	if ut == nil {
+		return nil, nil
+	}
+	return value(ut.Time, FMilli, Options{})
+}
+
+func (ut *Micros) String() string {
+	if ut == nil || ut.Time.IsZero() {
+		return "(none)"
+	}
+	return ut.Time.String()
+}
+
+func (ut Micros) MarshalJSON() ([]byte, error) {
+	return marshalJSON(ut.Time, FMicro)
+}
+
+func (ut *Micros) UnmarshalJSON(dat []byte) error {
+	return unmarshalJSON(&ut.Time, dat, TMicro, Options{})
+}
+
+// MarshalBinary uses Endian var
+func (ut Micros) MarshalBinary() ([]byte, error) {
+	return marshalBinary(ut.Time, FMicro)
+}
+
+// UnmarshalBinary uses Endian var
+func (ut *Micros) UnmarshalBinary(dat []byte) error {
+	return unmarshalBinary(&ut.Time, dat, TMicro)
+}
+
+func (ut *Micros) Scan(v interface{}) error {
+	return scan(&ut.Time, v, TMicro, Options{})
+}
+
+// Value is nil if zero time
+func (ut *Micros) Value() (driver.Value, error) {
+	if ut == nil {
+		return nil, nil
+	}
+	return value(ut.Time, FMicro, Options{})
+}
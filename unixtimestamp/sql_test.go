@@ -29,6 +29,8 @@ func TestSql(t *testing.T) {
 		t.Fatalf("sql.Open: %v", err)
 	}
 	defer db.Close()
+	unixtimestamp.DefaultClock = unixtimestamp.NewManualClock(time.Unix(1700000000, 123456789))
+	t.Cleanup(func() { unixtimestamp.DefaultClock = unixtimestamp.SystemClock })
 
 	var (
 		t1    *UnixTimestamp = Now()
@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 )
@@ -17,9 +18,9 @@ func New(t time.Time) *UnixTimestamp {
 	return &UnixTimestamp{Time: t}
 }
 
-// Now returns a new UnixTimestamp for the current time
+// Now returns a new UnixTimestamp for the current time (from DefaultClock)
 func Now() *UnixTimestamp {
-	return New(time.Now().UTC()) // nil tz and remove monotonic clock
+	return NowFrom(DefaultClock)
 }
 
 // Endian is the default byte order for UnixTimestamp for MarshalBinary interface
@@ -30,6 +31,8 @@ var Endian = binary.LittleEndian
 // Use Wrap(t) or Now() to create a UnixTimestamp
 type UnixTimestamp = UnixTimestampNull
 
+// UnixTimestampNotNull is a UnixTimestamp for NOT NULL columns and required json fields.
+// Zero time is stored as the unix epoch, and NULL/null input is an error. See notnull.go and Options.NotNull
 type UnixTimestampNotNull struct {
 	UnixTimestamp
 }
@@ -45,82 +48,197 @@ func (ut *UnixTimestamp) String() string {
 }
 
 func (ut UnixTimestamp) MarshalJSON() ([]byte, error) {
-	if ut.Time.After(zerotime) {
-		return []byte(strconv.Itoa(int(FuncFrom(ut.Time)))), nil
+	return marshalJSON(ut.Time, FuncFrom)
+}
+
+func (ut *UnixTimestamp) UnmarshalJSON(dat []byte) error {
+	return unmarshalJSON(&ut.Time, dat, FuncTo, Options{})
+}
+
+// MarshalBinary uses Endian var, set Endian to binary.BigEndian if needed
+func (ut UnixTimestamp) MarshalBinary() ([]byte, error) {
+	return marshalBinary(ut.Time, FuncFrom)
+}
+
+// UnmarshalBinary uses Endian var, set Endian to binary.BigEndian if needed
+func (ut *UnixTimestamp) UnmarshalBinary(dat []byte) error {
+	return unmarshalBinary(&ut.Time, dat, FuncTo)
+}
+
+// Scan implements sql.Scanner, also used by pgx v5 (native or database/sql).
+//
+// Accepts NULL, timestamp/timestamptz, integer columns (in FuncTo units), and text (numeric or postgres/RFC3339 datetime).
+func (u *UnixTimestamp) Scan(v interface{}) error {
+	return scan(&u.Time, v, FuncTo, Options{})
+}
+
+func marshalJSON(t time.Time, from func(time.Time) int64) ([]byte, error) {
+	if t.After(zerotime) {
+		return []byte(strconv.Itoa(int(from(t)))), nil
 	}
 	return []byte("0"), nil
 }
 
-func (ut *UnixTimestamp) UnmarshalJSON(dat []byte) error {
-	unix, err := strconv.Atoi(string(dat))
-	if err != nil {
-		return err
+// UnmarshalOptions for UnmarshalJSON (see Options). The zero value is strict: bare integers only.
+type UnmarshalOptions struct {
+	AllowQuoted  bool // accept "1700000000"
+	AllowRFC3339 bool // accept "2023-11-14T22:13:20Z"
+	AllowFloat   bool // accept 1700000000.25 (fraction of the type's unit, eg: seconds)
+}
+
+// FlexibleUnmarshal accepts quoted numbers, RFC3339 strings and floats, for input from javascript and various APIs
+var FlexibleUnmarshal = UnmarshalOptions{AllowQuoted: true, AllowRFC3339: true, AllowFloat: true}
+
+func unmarshalJSON(t *time.Time, dat []byte, to func(int64) time.Time, o Options) error {
+	s := string(dat)
+	if s == "null" {
+		return nil // json convention: no-op
 	}
-	if unix == 0 {
-		ut.Time = time.Time{}
+	opts := o.Unmarshal
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		str, err := strconv.Unquote(s)
+		if err != nil {
+			return err
+		}
+		if opts.AllowRFC3339 {
+			if parsed, err := time.Parse(time.RFC3339Nano, str); err == nil {
+				*t = parsed
+				return nil
+			}
+		}
+		if !opts.AllowQuoted {
+			return Errorf("unixtimestamp: unexpected string: %s", s)
+		}
+		s = str
+	}
+	unix, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		if !opts.AllowFloat {
+			return err
+		}
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil {
+			return err
+		}
+		if f == 0 {
+			*t = time.Time{}
+			return nil
+		}
+		whole, frac := math.Modf(f)
+		if err := fromUnix(t, int64(whole), to, o); err != nil {
+			return err
+		}
+		unit := to(1).Sub(to(0))
+		*t = t.Add(time.Duration(frac * float64(unit)))
 		return nil
 	}
-	ut.Time = FuncTo(int64(unix))
-	return nil
+	return fromUnix(t, unix, to, o)
 }
 
-// MarshalBinary uses Endian var, set Endian to binary.BigEndian if needed
-func (ut UnixTimestamp) MarshalBinary() ([]byte, error) {
+func marshalBinary(t time.Time, from func(time.Time) int64) ([]byte, error) {
 	var buf [8]byte
-	if ut.Time.After(zerotime) {
-		Endian.PutUint64(buf[:], uint64(FuncFrom(ut.Time)))
+	if t.After(zerotime) {
+		Endian.PutUint64(buf[:], uint64(from(t)))
 	}
 	return buf[:], nil
 }
 
-// UnmarshalBinary uses Endian var, set Endian to binary.BigEndian if needed
-func (ut *UnixTimestamp) UnmarshalBinary(dat []byte) error {
-	ut.Time = FuncTo(int64(Endian.Uint64(dat)))
-	if !ut.Time.After(zerotime) {
-		ut.Time = time.Time{}
+func unmarshalBinary(t *time.Time, dat []byte, to func(int64) time.Time) error {
+	if len(dat) < 8 {
+		return Errorf("unixtimestamp: binary too short: %d bytes", len(dat))
+	}
+	*t = to(int64(Endian.Uint64(dat)))
+	if !t.After(zerotime) {
+		*t = time.Time{}
 	}
 	return nil
 }
 
-func (u *UnixTimestamp) Scan(v interface{}) error {
-	var t time.Time
+func scan(t *time.Time, v interface{}, to func(int64) time.Time, o Options) error {
 	switch x := v.(type) {
 	case nil:
-		t = time.Time{}
+		*t = time.Time{}
 	case time.Time:
-		t = x
+		*t = x
 	case int64:
-		if x == 0 {
-			t = time.Time{}
-		} else {
-			t = FuncTo(x)
+		return fromUnix(t, x, to, o)
+	case int32, float64: // pgx int4, float8
+		return fromAny(t, x, to, o)
+	case []byte: // text protocol, eg: pgx/lib/pq timestamptz or bigint as text
+		return scan(t, string(x), to, o)
+	case string:
+		if x == "infinity" || x == "-infinity" {
+			return Errorf("unixtimestamp: cannot scan %s", x)
 		}
+		return fromAny(t, x, to, o)
 	default:
 		return Errorf("unsupported type: %T", v)
 	}
-	u.Time = t
-
 	return nil
 }
 
-// Value returns the time.Time value, regardless of zero time
-//
-// (does it work? no idea)
-// func (u UnixTimestampNotNull) Value() (driver.Value, error) {
-// 	log.Printf("UnixTimestampNotNull.Value: %v", u.Time)
-// 	return u.Time, nil
-// }
+func value(t time.Time, from func(time.Time) int64, o Options) (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	if o.SQLInteger {
+		return from(t), nil
+	}
+	return t, nil
+}
 
-// Value is returns nil if zero time. Wrap with UnixTimestampNotNull for not null
+// Value is returns nil if zero time. Wrap with UnixTimestampNotNull for not null. See Options.SQLInteger
 func (u *UnixTimestamp) Value() (driver.Value, error) {
-	if u == nil || u.Time.IsZero() {
+	if u == nil {
 		return nil, nil
 	}
-	return u.Time, nil
+	return value(u.Time, FuncFrom, Options{})
 }
 
+// NoCheckTimeScan disables unit mismatch detection (json, sql, yaml, toml, bson), see UnitMismatchError
 var NoCheckTimeScan bool
+
+// UnitMismatchError when an integer timestamp is clearly in the wrong unit,
+// eg: milliseconds while configured for seconds (year 55000), or seconds while configured for milliseconds (January 1970).
+type UnitMismatchError struct {
+	Value int64
+	Unit  time.Duration // configured unit
+	Time  time.Time     // what Value would have been
+}
+
+func (e *UnitMismatchError) Error() string {
+	return fmt.Sprintf("unixtimestamp: %d is not in %s units (would be %s), see Options.AutoCorrectUnits", e.Value, e.Unit, e.Time.UTC().Format(time.DateOnly))
+}
+
+// fromUnix converts with unit checking, 0 is zero time
+func fromUnix(t *time.Time, unix int64, to func(int64) time.Time, o Options) error {
+	if unix == 0 {
+		*t = time.Time{}
+		return nil
+	}
+	*t = to(unix)
+	if NoCheckTimeScan || unix < 0 {
+		return nil
+	}
+	unit := to(1).Sub(to(0))
+	tooSmall := unit < time.Second && t.Before(firstyear)
+	if !t.After(toofarfuture) && !tooSmall {
+		return nil
+	}
+	if !o.AutoCorrectUnits {
+		return &UnitMismatchError{Value: unix, Unit: unit, Time: *t}
+	}
+	for _, guess := range []func(int64) time.Time{TSeconds, TMilli, TMicro, TNano} {
+		if g := guess(unix); !g.Before(firstyear) && !g.After(toofarfuture) {
+			*t = g
+			return nil
+		}
+	}
+	return &UnitMismatchError{Value: unix, Unit: unit, Time: *t}
+}
+
 var zerotime = FuncTo(0)
+var firstyear = time.Unix(365*24*60*60, 0) // 1971
 var nineties = func() time.Time {
 	t, err := time.Parse("2006-01-02", "1990-01-01")
 	if err != nil {
@@ -130,9 +248,11 @@ var nineties = func() time.Time {
 }()
 
 // thousands of years in the future, to detect if someone uses milliseconds by accident
-var toofarfuture = FuncTo(nineties.UnixMilli())
+var toofarfuture = TSeconds(nineties.UnixMilli())
 
 // for switching between seconds, milliseconds, and microseconds (json/txt marshal)
+//
+// These are process-wide, prefer the Seconds, Millis and Micros types to mix precisions.
 
 var FuncTo = TSeconds   // consider TMilli
 var FuncFrom = FSeconds // Change FuncTo also. consider FMilli.
@@ -161,6 +281,10 @@ func TMicro(i int64) time.Time {
 	return time.UnixMicro(i)
 }
 
+func TNano(i int64) time.Time {
+	return time.Unix(0, i)
+}
+
 func toJson(v interface{}) string {
 	b, err := json.Marshal(v)
 	if err != nil {
@@ -0,0 +1,95 @@
+package unixtimestamp
+
+import (
+	"strconv"
+	"time"
+)
+
+// toml support without importing a toml package.
+// MarshalTOML/UnmarshalTOML for github.com/BurntSushi/toml,
+// MarshalText/UnmarshalText for github.com/pelletier/go-toml/v2 (and other text based encoders).
+// Native toml datetimes (offset, local, or date only) are accepted.
+
+// MarshalTOML as unix integer (0 for zero time)
+func (ut UnixTimestamp) MarshalTOML() ([]byte, error) {
+	return ut.MarshalText()
+}
+
+// UnmarshalTOML accepts a unix integer, a numeric string, or a toml datetime
+func (ut *UnixTimestamp) UnmarshalTOML(v interface{}) error {
+	return fromAny(&ut.Time, v, FuncTo, Options{})
+}
+
+// MarshalText as unix integer (0 for zero time)
+func (ut UnixTimestamp) MarshalText() ([]byte, error) {
+	return marshalText(ut.Time, FuncFrom), nil
+}
+
+// UnmarshalText accepts a unix integer or a datetime string
+func (ut *UnixTimestamp) UnmarshalText(dat []byte) error {
+	return fromAny(&ut.Time, string(dat), FuncTo, Options{})
+}
+
+// MarshalTOML as unix integer (0 for zero time)
+func (ut Seconds) MarshalTOML() ([]byte, error) {
+	return ut.MarshalText()
+}
+
+// UnmarshalTOML accepts a unix integer, a numeric string, or a toml datetime
+func (ut *Seconds) UnmarshalTOML(v interface{}) error {
+	return fromAny(&ut.Time, v, TSeconds, Options{})
+}
+
+// MarshalText as unix integer (0 for zero time)
+func (ut Seconds) MarshalText() ([]byte, error) {
+	return marshalText(ut.Time, FSeconds), nil
+}
+
+// UnmarshalText accepts a unix integer or a datetime string
+func (ut *Seconds) UnmarshalText(dat []byte) error {
+	return fromAny(&ut.Time, string(dat), TSeconds, Options{})
+}
+
+// MarshalTOML as unix integer (0 for zero time)
+func (ut Millis) MarshalTOML() ([]byte, error) {
+	return ut.MarshalText()
+}
+
+// UnmarshalTOML accepts a unix integer, a numeric string, or a toml datetime
+func (ut *Millis) UnmarshalTOML(v interface{}) error {
+	return fromAny(&ut.Time, v, TMilli, Options{})
+}
+
+// MarshalText as unix integer (0 for zero time)
+func (ut Millis) MarshalText() ([]byte, error) {
+	return marshalText(ut.Time, FMilli), nil
+}
+
+// UnmarshalText accepts a unix integer or a datetime string
+func (ut *Millis) UnmarshalText(dat []byte) error {
+	return fromAny(&ut.Time, string(dat), TMilli, Options{})
+}
+
+// MarshalTOML as unix integer (0 for zero time)
+func (ut Micros) MarshalTOML() ([]byte, error) {
+	return ut.MarshalText()
+}
+
+// UnmarshalTOML accepts a unix integer, a numeric string, or a toml datetime
+func (ut *Micros) UnmarshalTOML(v interface{}) error {
+	return fromAny(&ut.Time, v, TMicro, Options{})
+}
+
+// MarshalText as unix integer (0 for zero time)
+func (ut Micros) MarshalText() ([]byte, error) {
+	return marshalText(ut.Time, FMicro), nil
+}
+
+// UnmarshalText accepts a unix integer or a datetime string
+func (ut *Micros) UnmarshalText(dat []byte) error {
+	return fromAny(&ut.Time, string(dat), TMicro, Options{})
+}
+
+func marshalText(t time.Time, from func(time.Time) int64) []byte {
+	return strconv.AppendInt(nil, unixInt(t, from), 10)
+}
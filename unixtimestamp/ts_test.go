@@ -1,13 +1,18 @@
 package unixtimestamp
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"flag"
 	"testing"
 	"time"
 )
 
 func TestMillieconds(t *testing.T) {
-	var t1 = time.Now()
+	var t1 = time.UnixMilli(1700000000123)
+	useClock(t, t1)
 	// wow now its microseconds
 	FuncFrom = FMilli
 	FuncTo = TMilli
@@ -16,43 +21,370 @@ func TestMillieconds(t *testing.T) {
 		FuncTo = TSeconds
 	})
 	buf, err := Now().MarshalJSON()
-	if err != nil {
-		t.Fatalf("MarshalJSON: %v", err)
+	if err != nil || string(buf) != "1700000000123" {
+		t.Fatalf("MarshalJSON: %s %v", buf, err)
 	}
-	println("json:    ", string(buf))
 	var y *UnixTimestamp
 	json.Unmarshal(buf, &y)
 	if y == nil {
 		t.Fatalf("UnmarshalJSON: nil")
 	}
-	println("y:       ", y.String())
-	if y.After(t1.Add(time.Second)) {
-		t.Fatalf("After")
-	}
-	if y.Before(t1.Add(-time.Second)) {
-		t.Fatalf("Before")
+	if !y.Equal(t1) {
+		t.Fatalf("round trip: %v", y)
 	}
 }
 
 func TestTimestamp(t *testing.T) {
-	var t1 = time.Now()
+	var t1 = time.Unix(1700000000, 999999999)
+	useClock(t, t1)
 	FuncFrom = FSeconds
 	FuncTo = TSeconds
 	var y *UnixTimestamp
 	buf, err := Now().MarshalJSON()
-	if err != nil {
-		t.Fatalf("MarshalJSON: %v", err)
+	if err != nil || string(buf) != "1700000000" {
+		t.Fatalf("MarshalJSON: %s %v", buf, err)
 	}
-	println("json:    ", string(buf))
 	json.Unmarshal(buf, &y)
 	if y == nil {
 		t.Fatalf("UnmarshalJSON: nil")
 	}
-	println("y:       ", y.String())
-	if y.After(t1.Add(time.Second)) {
-		t.Fatalf("After")
+	if !y.Equal(t1.Truncate(time.Second)) {
+		t.Fatalf("round trip: %v", y)
+	}
+}
+
+// useClock for Now() until the test ends
+func useClock(t *testing.T, now time.Time) *ManualClock {
+	clock := NewManualClock(now)
+	DefaultClock = clock
+	t.Cleanup(func() { DefaultClock = SystemClock })
+	return clock
+}
+
+func TestPrecisionTypes(t *testing.T) {
+	var t1 = time.UnixMicro(1700000000123456)
+	type mixed struct {
+		S Seconds
+		M Millis
+		U Micros
+	}
+	buf, err := json.Marshal(mixed{Seconds{t1}, Millis{t1}, Micros{t1}})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(buf) != `{"S":1700000000,"M":1700000000123,"U":1700000000123456}` {
+		t.Fatalf("unexpected json: %s", buf)
+	}
+	var y mixed
+	if err := json.Unmarshal(buf, &y); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !y.U.Equal(t1) || !y.M.Equal(t1.Truncate(time.Millisecond)) || !y.S.Equal(t1.Truncate(time.Second)) {
+		t.Fatalf("round trip: %v %v %v", y.S, y.M, y.U)
+	}
+}
+
+func TestFlexibleUnmarshal(t *testing.T) {
+	var y UnixTimestamp
+	if err := json.Unmarshal([]byte(`"1700000000"`), &y); err == nil {
+		t.Fatalf("strict: expected error for quoted number")
+	}
+	var f With[flexible]
+	want := time.Unix(1700000000, 0)
+	for _, in := range []string{`1700000000`, `"1700000000"`, `"2023-11-14T22:13:20Z"`, `1700000000.0`} {
+		if err := json.Unmarshal([]byte(in), &f); err != nil || !f.Equal(want) {
+			t.Fatalf("%s: %v %v", in, f.Time, err)
+		}
+	}
+	if err := json.Unmarshal([]byte(`1700000000.25`), &f); err != nil || !f.Equal(want.Add(time.Second/4)) {
+		t.Fatalf("float: %v %v", f.Time, err)
+	}
+	if err := json.Unmarshal([]byte(`"1700000000"`), &y); err == nil {
+		t.Fatalf("strict type changed by With")
+	}
+	if b, err := json.Marshal(NewWith[flexible](want)); err != nil || string(b) != "1700000000" {
+		t.Fatalf("Marshal: %s %v", b, err)
+	}
+}
+
+type flexible struct{}
+
+func (flexible) Options() Options { return Options{Unmarshal: FlexibleUnmarshal} }
+
+func TestFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var since, until UnixTimestamp
+	fs.Var(&since, "since", "")
+	fs.Var(&until, "until", "")
+	if err := fs.Parse([]string{"--since", "1700000000", "--until", "2023-11-15T00:00:00Z"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if since.Unix() != 1700000000 || until.Unix() != 1700006400 {
+		t.Fatalf("unexpected: %v %v", since.Time, until.Time)
+	}
+	if err := fs.Parse([]string{"--since", "yesterday"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	for _, in := range []interface{}{1700000000, "1700000000", "2023-11-14T22:13:20Z", time.Unix(1700000000, 0)} {
+		var y UnixTimestamp
+		err := y.UnmarshalYAML(func(v interface{}) error {
+			*(v.(*interface{})) = in
+			return nil
+		})
+		if err != nil || y.Unix() != 1700000000 {
+			t.Fatalf("%v: %v %v", in, y.Time, err)
+		}
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	for _, in := range []string{"1700000000", "2023-11-14T22:13:20Z", "2023-11-14 22:13:20", "2023-11-14T22:13:20"} {
+		var y UnixTimestamp
+		if err := y.UnmarshalText([]byte(in)); err != nil || y.Unix() != 1700000000 {
+			t.Fatalf("%s: %v %v", in, y.Time, err)
+		}
+		if b, _ := y.MarshalText(); string(b) != "1700000000" {
+			t.Fatalf("MarshalText: %s", b)
+		}
+	}
+}
+
+func TestBSONValue(t *testing.T) {
+	x := UnixTimestamp{time.Unix(1700000000, 0)}
+	for _, m := range []interface {
+		MarshalBSONValue() (byte, []byte, error)
+	}{x, With[nativeBSON]{x}} {
+		typ, data, err := m.MarshalBSONValue()
+		if err != nil {
+			t.Fatalf("MarshalBSONValue: %v", err)
+		}
+		if _, native := m.(With[nativeBSON]); native != (typ == bsonDatetime) {
+			t.Fatalf("%T: bson type 0x%02x", m, typ)
+		}
+		var y UnixTimestamp
+		if err := y.UnmarshalBSONValue(typ, data); err != nil || !y.Equal(x.Time) {
+			t.Fatalf("%T: %v %v", m, y.Time, err)
+		}
+	}
+}
+
+type nativeBSON struct{}
+
+func (nativeBSON) Options() Options { return Options{BSONDatetime: true} }
+
+func TestScanPostgres(t *testing.T) {
+	for _, in := range []interface{}{int64(1700000000), int32(1700000000), []byte("1700000000"), "2023-11-14 22:13:20+00", []byte("2023-11-14 23:13:20.000+01"), time.Unix(1700000000, 0)} {
+		var y UnixTimestamp
+		if err := y.Scan(in); err != nil || y.Unix() != 1700000000 {
+			t.Fatalf("%T %v: %v %v", in, in, y.Time, err)
+		}
+	}
+	var y UnixTimestamp
+	if err := y.Scan("infinity"); err == nil {
+		t.Fatalf("expected error for infinity")
+	}
+	if err := y.Scan(nil); err != nil || !y.IsZero() {
+		t.Fatalf("nil: %v %v", y.Time, err)
+	}
+}
+
+func TestSQLInteger(t *testing.T) {
+	v, err := NewWith[sqlInteger](time.Unix(1700000000, 0)).Value()
+	if err != nil || v != int64(1700000000) {
+		t.Fatalf("Value: %v %v", v, err)
+	}
+	if v, _ := (&With[sqlInteger]{}).Value(); v != nil {
+		t.Fatalf("zero Value: %v", v)
+	}
+	if v, _ := NewMillis(time.UnixMilli(1700000000123)).Value(); v != time.UnixMilli(1700000000123) {
+		t.Fatalf("default Value: %v", v)
+	}
+	var y With[sqlInteger]
+	if err := y.Scan(int64(1700000000)); err != nil || y.Unix() != 1700000000 {
+		t.Fatalf("Scan: %v %v", y.Time, err)
+	}
+}
+
+type sqlInteger struct{}
+
+func (sqlInteger) Options() Options { return Options{SQLInteger: true} }
+
+func TestZonedTimestamp(t *testing.T) {
+	zone := time.FixedZone("XYZ", -5*3600)
+	x := NewZoned(time.Unix(1700000000, 0).In(zone))
+	buf, err := json.Marshal(x)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(buf) != `{"unix":1700000000,"tz":"XYZ","offset":-18000}` {
+		t.Fatalf("unexpected json: %s", buf)
+	}
+	var y ZonedTimestamp
+	if err := json.Unmarshal(buf, &y); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if name, offset := y.Zone(); name != "XYZ" || offset != -18000 || !y.Equal(x.Time) {
+		t.Fatalf("zone not restored: %v", y.Time)
+	}
+}
+
+func TestManualClock(t *testing.T) {
+	clock := useClock(t, time.Unix(1700000000, 0))
+	buf, err := Now().MarshalJSON()
+	if err != nil || string(buf) != "1700000000" {
+		t.Fatalf("MarshalJSON: %s %v", buf, err)
+	}
+	clock.Add(time.Hour)
+	if Now().Unix() != 1700003600 {
+		t.Fatalf("Add: %v", Now())
+	}
+}
+
+func TestUnitMismatch(t *testing.T) {
+	var y UnixTimestamp
+	var mismatch *UnitMismatchError
+	if err := json.Unmarshal([]byte("1700000000123"), &y); !errors.As(err, &mismatch) {
+		t.Fatalf("millis as seconds: expected UnitMismatchError, got %v (%v)", err, y.Time)
+	}
+	var m Millis
+	if err := m.Scan(int64(1700000000)); !errors.As(err, &mismatch) {
+		t.Fatalf("seconds as millis: expected UnitMismatchError, got %v (%v)", err, m.Time)
+	}
+	var a With[autoCorrect]
+	if err := json.Unmarshal([]byte("1700000000123"), &a); err != nil || !a.Equal(time.UnixMilli(1700000000123)) {
+		t.Fatalf("autocorrect json: %v %v", a.Time, err)
+	}
+	if err := a.Scan(int64(1700000000123456)); err != nil || !a.Equal(time.UnixMicro(1700000000123456)) {
+		t.Fatalf("autocorrect scan: %v %v", a.Time, err)
+	}
+	if err := a.UnmarshalText([]byte("1700000000123")); err != nil || !a.Equal(time.UnixMilli(1700000000123)) {
+		t.Fatalf("autocorrect text: %v %v", a.Time, err)
+	}
+	if err := json.Unmarshal([]byte("1700000000123"), &y); !errors.As(err, &mismatch) {
+		t.Fatalf("default type changed by With: %v", err)
+	}
+}
+
+type autoCorrect struct{}
+
+func (autoCorrect) Options() Options { return Options{AutoCorrectUnits: true} }
+
+func TestNotNull(t *testing.T) {
+	v, err := (*UnixTimestampNotNull)(nil).Value()
+	if err != nil || v != epoch {
+		t.Fatalf("nil Value: %v %v", v, err)
+	}
+	var y UnixTimestampNotNull
+	if err := y.Scan(nil); err != ErrNull {
+		t.Fatalf("Scan(nil): expected ErrNull, got %v", err)
+	}
+	if err := y.Scan(epoch); err != nil || !y.IsZero() {
+		t.Fatalf("Scan(epoch): %v %v", y.Time, err)
+	}
+	var s struct{ T UnixTimestampNotNull }
+	if err := json.Unmarshal([]byte(`{"T":null}`), &s); err != ErrNull {
+		t.Fatalf("null json: expected ErrNull, got %v", err)
+	}
+}
+
+func TestNotNullZero(t *testing.T) {
+	sentinel := time.Time{}
+	v, err := (&With[notNullSentinel]{}).Value()
+	if err != nil || v != sentinel {
+		t.Fatalf("zero Value: %v %v", v, err)
+	}
+	var y With[notNullSentinel]
+	if err := y.Scan(nil); err != ErrNull {
+		t.Fatalf("Scan(nil): expected ErrNull, got %v", err)
+	}
+	if err := y.Scan(sentinel); err != nil || !y.IsZero() {
+		t.Fatalf("Scan(sentinel): %v %v", y.Time, err)
+	}
+	if err := y.Scan(epoch); err != nil || !y.Equal(epoch) {
+		t.Fatalf("Scan(epoch): %v %v", y.Time, err)
+	}
+	if err := json.Unmarshal([]byte(`null`), &y); err != ErrNull {
+		t.Fatalf("null json: expected ErrNull, got %v", err)
+	}
+	if v, _ := (*UnixTimestampNotNull)(nil).Value(); v != epoch {
+		t.Fatalf("UnixTimestampNotNull changed by With: %v", v)
+	}
+}
+
+type notNullSentinel struct{}
+
+func (notNullSentinel) Options() Options {
+	return Options{NotNull: true, NotNullZero: &time.Time{}}
+}
+
+func TestBinaryFormats(t *testing.T) {
+	a, b := UnixTimestamp{time.Unix(1700000000, 0)}, UnixTimestamp{time.Unix(1700000256, 0)}
+	for _, f := range []BinaryFormat{KeyFormat, Key32Format, CompactFormat, {}} {
+		ka, err := a.MarshalBinaryWith(f)
+		if err != nil {
+			t.Fatalf("%+v: %v", f, err)
+		}
+		kb, _ := b.MarshalBinaryWith(f)
+		if f.Order == binary.BigEndian && bytes.Compare(ka, kb) >= 0 {
+			t.Fatalf("%+v: keys do not sort", f)
+		}
+		var y UnixTimestamp
+		if err := y.UnmarshalBinaryWith(f, ka); err != nil || !y.Equal(a.Time) {
+			t.Fatalf("%+v: %v %v", f, y.Time, err)
+		}
+	}
+}
+
+func TestBinaryKeysPre1970(t *testing.T) {
+	times := []time.Time{time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), time.Unix(-1, 0), time.Date(1971, 6, 1, 0, 0, 0, 0, time.UTC), time.Unix(1700000000, 0)}
+	var prev []byte
+	for _, tm := range times {
+		k, err := Millis{tm}.MarshalBinaryWith(KeyFormat)
+		if err != nil {
+			t.Fatalf("%v: %v", tm, err)
+		}
+		if bytes.Compare(prev, k) >= 0 {
+			t.Fatalf("%v: key does not sort after %x: %x", tm, prev, k)
+		}
+		prev = k
+		var m Millis
+		if err := m.UnmarshalBinaryWith(KeyFormat, k); err != nil || !m.Equal(tm) {
+			t.Fatalf("%v: %v %v", tm, m.Time, err)
+		}
+		c, _ := Seconds{tm}.MarshalBinaryWith(CompactFormat)
+		var sec Seconds
+		if err := sec.UnmarshalBinaryWith(CompactFormat, c); err != nil || !sec.Equal(tm) {
+			t.Fatalf("%v compact: %v %v", tm, sec.Time, err)
+		}
+	}
+	if _, err := (Micros{times[0]}).MarshalBinaryWith(Key32Format); err == nil {
+		t.Fatal("pre-1970 in 4 bytes: expected error")
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	ut := New(time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)) // DST starts in New York
+	if got := ut.StartOfDay(ny).Time; !got.Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, ny)) {
+		t.Fatalf("StartOfDay: %v", got)
+	}
+	if got := ut.EndOfDay(nil).Time; !got.Equal(time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC).Add(-1)) {
+		t.Fatalf("EndOfDay: %v", got)
+	}
+	if got := ut.TruncateTo(time.Hour).Time; got.Minute() != 0 {
+		t.Fatalf("TruncateTo: %v", got)
+	}
+	if got := ut.AddDays(1).AddMonths(-1).Time; !got.Equal(time.Date(2024, 2, 11, 12, 30, 0, 0, time.UTC)) {
+		t.Fatalf("AddDays/AddMonths: %v", got)
 	}
-	if y.Before(t1.Add(-time.Second)) {
-		t.Fatalf("Before")
+	var none *UnixTimestamp
+	if !none.IsBefore(ut) || !ut.IsAfter(none) || !none.IsEqual(&UnixTimestamp{}) || ut.CompareTo(ut.AddDays(0)) != 0 {
+		t.Fatal("nil comparisons")
 	}
 }
@@ -0,0 +1,116 @@
+package unixtimestamp
+
+import (
+	"strconv"
+	"time"
+)
+
+// yaml support without importing a yaml package.
+// Signatures work with gopkg.in/yaml.v2, gopkg.in/yaml.v3 and sigs.k8s.io/yaml (via json).
+
+// MarshalYAML as unix integer (0 for zero time)
+func (ut UnixTimestamp) MarshalYAML() (interface{}, error) {
+	return unixInt(ut.Time, FuncFrom), nil
+}
+
+// UnmarshalYAML accepts a unix integer, a numeric string, or a yaml/RFC3339 timestamp
+func (ut *UnixTimestamp) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(&ut.Time, unmarshal, FuncTo, Options{})
+}
+
+// MarshalYAML as unix integer (0 for zero time)
+func (ut Seconds) MarshalYAML() (interface{}, error) {
+	return unixInt(ut.Time, FSeconds), nil
+}
+
+// UnmarshalYAML accepts a unix integer, a numeric string, or a yaml/RFC3339 timestamp
+func (ut *Seconds) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(&ut.Time, unmarshal, TSeconds, Options{})
+}
+
+// MarshalYAML as unix integer (0 for zero time)
+func (ut Millis) MarshalYAML() (interface{}, error) {
+	return unixInt(ut.Time, FMilli), nil
+}
+
+// UnmarshalYAML accepts a unix integer, a numeric string, or a yaml/RFC3339 timestamp
+func (ut *Millis) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(&ut.Time, unmarshal, TMilli, Options{})
+}
+
+// MarshalYAML as unix integer (0 for zero time)
+func (ut Micros) MarshalYAML() (interface{}, error) {
+	return unixInt(ut.Time, FMicro), nil
+}
+
+// UnmarshalYAML accepts a unix integer, a numeric string, or a yaml/RFC3339 timestamp
+func (ut *Micros) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(&ut.Time, unmarshal, TMicro, Options{})
+}
+
+// unixInt is 0 for zero time
+func unixInt(t time.Time, from func(time.Time) int64) int64 {
+	if t.After(zerotime) {
+		return from(t)
+	}
+	return 0
+}
+
+func unmarshalYAML(t *time.Time, unmarshal func(interface{}) error, to func(int64) time.Time, o Options) error {
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return fromAny(t, v, to, o)
+}
+
+// fromAny handles decoded config values (yaml, toml)
+func fromAny(t *time.Time, v interface{}, to func(int64) time.Time, o Options) error {
+	switch x := v.(type) {
+	case nil:
+		*t = time.Time{}
+	case time.Time:
+		*t = x
+	case int:
+		return fromAny(t, int64(x), to, o)
+	case int32:
+		return fromAny(t, int64(x), to, o)
+	case int64:
+		return fromUnix(t, x, to, o)
+	case uint64:
+		return fromAny(t, int64(x), to, o)
+	case float64:
+		return fromAny(t, int64(x), to, o)
+	case string:
+		if unix, err := strconv.ParseInt(x, 10, 64); err == nil {
+			return fromAny(t, unix, to, o)
+		}
+		parsed, err := parseDatetime(x)
+		if err != nil {
+			return err
+		}
+		*t = parsed
+	default:
+		return Errorf("unsupported type: %T", v)
+	}
+	return nil
+}
+
+// RFC3339, toml's local/space separated variants, and postgres text output (local times are UTC)
+var datetimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	time.DateOnly,
+}
+
+func parseDatetime(s string) (time.Time, error) {
+	for _, layout := range datetimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, Errorf("unixtimestamp: expected unix integer or RFC3339: %q", s)
+}
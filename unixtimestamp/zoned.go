@@ -0,0 +1,68 @@
+package unixtimestamp
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ZonedTimestamp is a time.Time that marshals to/from {"unix":..., "tz":..., "offset":...}, keeping the original location.
+//
+// Unix is in FuncFrom units. The tz name is tried first (time.LoadLocation), falling back to a fixed zone with offset (seconds east of UTC).
+type ZonedTimestamp struct {
+	time.Time
+}
+
+// NewZoned existing time.Time (location is kept)
+func NewZoned(t time.Time) *ZonedTimestamp {
+	return &ZonedTimestamp{Time: t}
+}
+
+type zonedJSON struct {
+	Unix   int64  `json:"unix"`
+	TZ     string `json:"tz,omitempty"`
+	Offset int    `json:"offset"`
+}
+
+func (zt *ZonedTimestamp) String() string {
+	if zt == nil || zt.Time.IsZero() {
+		return "(none)"
+	}
+	return zt.Time.String()
+}
+
+func (zt ZonedTimestamp) MarshalJSON() ([]byte, error) {
+	if !zt.Time.After(zerotime) {
+		return json.Marshal(zonedJSON{})
+	}
+	name, offset := zt.Time.Zone()
+	if loc := zt.Time.Location().String(); loc != "Local" {
+		name = loc // prefer IANA name, eg: "America/New_York" over "EST"
+	}
+	return json.Marshal(zonedJSON{Unix: FuncFrom(zt.Time), TZ: name, Offset: offset})
+}
+
+func (zt *ZonedTimestamp) UnmarshalJSON(dat []byte) error {
+	if string(dat) == "null" {
+		return nil
+	}
+	var z zonedJSON
+	if err := json.Unmarshal(dat, &z); err != nil {
+		return err
+	}
+	if z.Unix == 0 {
+		zt.Time = time.Time{}
+		return nil
+	}
+	zt.Time = FuncTo(z.Unix).In(zoneLocation(z.TZ, z.Offset))
+	return nil
+}
+
+func zoneLocation(name string, offset int) *time.Location {
+	if name == "UTC" || (name == "" && offset == 0) {
+		return time.UTC
+	}
+	if loc, err := time.LoadLocation(name); err == nil && name != "" && name != "Local" {
+		return loc
+	}
+	return time.FixedZone(name, offset)
+}